@@ -0,0 +1,1739 @@
+// Package algo provides graph, tree, sorting, and dynamic-programming
+// algorithms shared across the project's Go utilities.
+package algo
+
+import (
+	"container/heap"
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+)
+
+// ===== BINARY SEARCH TREE =====
+
+// TreeNode represents a node in a binary search tree.
+type TreeNode struct {
+	Value int
+	Left  *TreeNode
+	Right *TreeNode
+}
+
+// BST implements a binary search tree.
+type BST struct {
+	Root *TreeNode
+	mu   sync.RWMutex
+}
+
+// Insert adds a value to the BST.
+func (t *BST) Insert(value int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	newNode := &TreeNode{Value: value}
+
+	if t.Root == nil {
+		t.Root = newNode
+		return
+	}
+
+	current := t.Root
+	for {
+		if value < current.Value {
+			if current.Left == nil {
+				current.Left = newNode
+				return
+			}
+			current = current.Left
+		} else if value > current.Value {
+			if current.Right == nil {
+				current.Right = newNode
+				return
+			}
+			current = current.Right
+		} else {
+			return // Duplicate
+		}
+	}
+}
+
+// Find searches for a value in the BST.
+func (t *BST) Find(value int) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	current := t.Root
+	for current != nil {
+		if value == current.Value {
+			return true
+		} else if value < current.Value {
+			current = current.Left
+		} else {
+			current = current.Right
+		}
+	}
+	return false
+}
+
+// Delete removes a value from the BST.
+func (t *BST) Delete(value int) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var parent *TreeNode
+	current := t.Root
+	isLeft := false
+
+	for current != nil {
+		if value == current.Value {
+			break
+		}
+		parent = current
+		if value < current.Value {
+			current = current.Left
+			isLeft = true
+		} else {
+			current = current.Right
+			isLeft = false
+		}
+	}
+
+	if current == nil {
+		return false
+	}
+
+	// Leaf node
+	if current.Left == nil && current.Right == nil {
+		if current == t.Root {
+			t.Root = nil
+		} else if isLeft {
+			parent.Left = nil
+		} else {
+			parent.Right = nil
+		}
+	} else if current.Right == nil {
+		// One child (left)
+		if current == t.Root {
+			t.Root = current.Left
+		} else if isLeft {
+			parent.Left = current.Left
+		} else {
+			parent.Right = current.Left
+		}
+	} else if current.Left == nil {
+		// One child (right)
+		if current == t.Root {
+			t.Root = current.Right
+		} else if isLeft {
+			parent.Left = current.Right
+		} else {
+			parent.Right = current.Right
+		}
+	} else {
+		// Two children
+		successor := t.findMin(current.Right)
+		current.Value = successor
+		t.deleteMin(current, current.Right)
+	}
+
+	return true
+}
+
+func (t *BST) findMin(node *TreeNode) int {
+	for node.Left != nil {
+		node = node.Left
+	}
+	return node.Value
+}
+
+func (t *BST) deleteMin(parent, node *TreeNode) {
+	for node.Left != nil {
+		parent = node
+		node = node.Left
+	}
+	if parent.Left == node {
+		parent.Left = node.Right
+	} else {
+		parent.Right = node.Right
+	}
+}
+
+// InOrder returns values in sorted order.
+func (t *BST) InOrder() []int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	var result []int
+	t.inOrderHelper(t.Root, &result)
+	return result
+}
+
+func (t *BST) inOrderHelper(node *TreeNode, result *[]int) {
+	if node != nil {
+		t.inOrderHelper(node.Left, result)
+		*result = append(*result, node.Value)
+		t.inOrderHelper(node.Right, result)
+	}
+}
+
+// Merge inserts all of other's values into t, skipping duplicates per
+// Insert semantics. Rather than O(n log n) individual inserts, it
+// merges the two sorted in-order sequences and rebuilds t as a
+// balanced tree. Other's values are snapshotted under its read lock
+// before t's write lock is taken, so the locks are never nested and
+// concurrent a.Merge(b) / b.Merge(a) cannot deadlock; the merge
+// reflects other's contents at the moment of the snapshot.
+func (t *BST) Merge(other *BST) {
+	if other == nil || other == t {
+		return
+	}
+
+	other.mu.RLock()
+	var right []int
+	other.inOrderHelper(other.Root, &right)
+	other.mu.RUnlock()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var left []int
+	t.inOrderHelper(t.Root, &left)
+
+	union := make([]int, 0, len(left)+len(right))
+	i, j := 0, 0
+	for i < len(left) || j < len(right) {
+		switch {
+		case j >= len(right) || (i < len(left) && left[i] < right[j]):
+			union = append(union, left[i])
+			i++
+		case i >= len(left) || right[j] < left[i]:
+			union = append(union, right[j])
+			j++
+		default: // equal, keep one
+			union = append(union, left[i])
+			i++
+			j++
+		}
+	}
+
+	t.Root = buildBalanced(union)
+}
+
+// buildBalanced turns a sorted slice into a height-balanced subtree.
+func buildBalanced(sorted []int) *TreeNode {
+	if len(sorted) == 0 {
+		return nil
+	}
+	mid := len(sorted) / 2
+	return &TreeNode{
+		Value: sorted[mid],
+		Left:  buildBalanced(sorted[:mid]),
+		Right: buildBalanced(sorted[mid+1:]),
+	}
+}
+
+// ===== PRIORITY QUEUE =====
+
+// PQItem represents an item in the priority queue.
+type PQItem struct {
+	Value    interface{}
+	Priority int
+	Index    int
+}
+
+// PriorityQueue implements heap.Interface.
+type PriorityQueue []*PQItem
+
+func (pq PriorityQueue) Len() int { return len(pq) }
+
+func (pq PriorityQueue) Less(i, j int) bool {
+	return pq[i].Priority < pq[j].Priority
+}
+
+func (pq PriorityQueue) Swap(i, j int) {
+	pq[i], pq[j] = pq[j], pq[i]
+	pq[i].Index = i
+	pq[j].Index = j
+}
+
+func (pq *PriorityQueue) Push(x interface{}) {
+	n := len(*pq)
+	item := x.(*PQItem)
+	item.Index = n
+	*pq = append(*pq, item)
+}
+
+func (pq *PriorityQueue) Pop() interface{} {
+	old := *pq
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	item.Index = -1
+	*pq = old[0 : n-1]
+	return item
+}
+
+// NewPriorityQueueFrom builds a priority queue from an existing slice
+// of items in O(n) using heap.Init, instead of n individual pushes.
+// Each item's Index is set to its heap position.
+func NewPriorityQueueFrom(items []*PQItem) *PriorityQueue {
+	pq := make(PriorityQueue, len(items))
+	copy(pq, items)
+	for i, item := range pq {
+		item.Index = i
+	}
+	heap.Init(&pq)
+	return &pq
+}
+
+// ===== GRAPH WITH DIJKSTRA =====
+
+// Edge represents a weighted edge.
+type Edge struct {
+	To     int
+	Weight int
+}
+
+// Graph represents a weighted directed graph.
+type Graph struct {
+	Vertices int
+	Edges    map[int][]Edge
+	mu       sync.RWMutex
+}
+
+// NewGraph creates a new graph.
+func NewGraph(vertices int) *Graph {
+	return &Graph{
+		Vertices: vertices,
+		Edges:    make(map[int][]Edge),
+	}
+}
+
+// AddVertex grows the graph by one vertex and returns its id.
+func (g *Graph) AddVertex() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	id := g.Vertices
+	g.Vertices++
+	return id
+}
+
+// AddEdge adds a directed edge, growing Vertices if from or to exceed
+// the current count so traversals keep sizing their arrays correctly.
+func (g *Graph) AddEdge(from, to, weight int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if from >= g.Vertices {
+		g.Vertices = from + 1
+	}
+	if to >= g.Vertices {
+		g.Vertices = to + 1
+	}
+	g.Edges[from] = append(g.Edges[from], Edge{To: to, Weight: weight})
+}
+
+// AddOrUpdateEdge adds a directed edge, but unlike AddEdge it replaces
+// the weight of an existing edge to the same target instead of
+// appending a parallel edge. AddEdge keeps the append behavior for
+// callers that want multigraph semantics.
+func (g *Graph) AddOrUpdateEdge(from, to, weight int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if from >= g.Vertices {
+		g.Vertices = from + 1
+	}
+	if to >= g.Vertices {
+		g.Vertices = to + 1
+	}
+	for i, edge := range g.Edges[from] {
+		if edge.To == to {
+			g.Edges[from][i].Weight = weight
+			return
+		}
+	}
+	g.Edges[from] = append(g.Edges[from], Edge{To: to, Weight: weight})
+}
+
+// Neighbors returns a copy of the out-edges of v, taken under the read
+// lock so callers never race with AddEdge.
+func (g *Graph) Neighbors(v int) []Edge {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	edges := g.Edges[v]
+	result := make([]Edge, len(edges))
+	copy(result, edges)
+	return result
+}
+
+// OutDegree returns the number of edges leaving v.
+func (g *Graph) OutDegree(v int) int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return len(g.Edges[v])
+}
+
+// InDegree returns the number of edges entering v. This scans every
+// adjacency list, so it costs O(E).
+func (g *Graph) InDegree(v int) int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	count := 0
+	for _, edges := range g.Edges {
+		for _, edge := range edges {
+			if edge.To == v {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// graphJSON is the serialized form of a Graph; the lock is not part of
+// the persisted state.
+type graphJSON struct {
+	Vertices int            `json:"vertices"`
+	Edges    map[int][]Edge `json:"edges"`
+}
+
+// MarshalJSON serializes the vertex count and adjacency map under the
+// read lock.
+func (g *Graph) MarshalJSON() ([]byte, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return json.Marshal(graphJSON{Vertices: g.Vertices, Edges: g.Edges})
+}
+
+// UnmarshalJSON restores a graph serialized with MarshalJSON,
+// replacing the receiver's contents under the write lock.
+func (g *Graph) UnmarshalJSON(data []byte) error {
+	var raw graphJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw.Edges == nil {
+		raw.Edges = make(map[int][]Edge)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.Vertices = raw.Vertices
+	g.Edges = raw.Edges
+	return nil
+}
+
+// ToMatrix exports the graph as a Vertices x Vertices weight matrix
+// with 0 on the diagonal and 1<<31-1 (the Dijkstra sentinel) for
+// absent edges. Parallel edges collapse to the minimum weight.
+func (g *Graph) ToMatrix() [][]int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	matrix := make([][]int, g.Vertices)
+	for i := range matrix {
+		matrix[i] = make([]int, g.Vertices)
+		for j := range matrix[i] {
+			if i != j {
+				matrix[i][j] = 1<<31 - 1 // MaxInt
+			}
+		}
+	}
+	for from, edges := range g.Edges {
+		for _, edge := range edges {
+			if edge.Weight < matrix[from][edge.To] {
+				matrix[from][edge.To] = edge.Weight
+			}
+		}
+	}
+	return matrix
+}
+
+// GraphFromMatrix builds a graph from a weight matrix produced by
+// ToMatrix, skipping diagonal and sentinel entries.
+func GraphFromMatrix(m [][]int) *Graph {
+	g := NewGraph(len(m))
+	for from, row := range m {
+		for to, weight := range row {
+			if from == to || weight == 1<<31-1 {
+				continue
+			}
+			g.AddEdge(from, to, weight)
+		}
+	}
+	return g
+}
+
+// Dijkstra finds shortest paths from source.
+func (g *Graph) Dijkstra(source int) []int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	dist := make([]int, g.Vertices)
+	for i := range dist {
+		dist[i] = 1<<31 - 1 // MaxInt
+	}
+	dist[source] = 0
+
+	pq := make(PriorityQueue, 0)
+	heap.Init(&pq)
+	heap.Push(&pq, &PQItem{Value: source, Priority: 0})
+
+	visited := make([]bool, g.Vertices)
+
+	for pq.Len() > 0 {
+		item := heap.Pop(&pq).(*PQItem)
+		u := item.Value.(int)
+
+		if visited[u] {
+			continue
+		}
+		visited[u] = true
+
+		for _, edge := range g.Edges[u] {
+			if visited[edge.To] {
+				continue
+			}
+			newDist := dist[u] + edge.Weight
+			if newDist < dist[edge.To] {
+				dist[edge.To] = newDist
+				heap.Push(&pq, &PQItem{Value: edge.To, Priority: newDist})
+			}
+		}
+	}
+
+	return dist
+}
+
+// DijkstraMulti finds each vertex's shortest distance to the nearest
+// of several sources by seeding the queue with every source at
+// distance 0. Empty sources yields all-MaxInt.
+func (g *Graph) DijkstraMulti(sources []int) []int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	dist := make([]int, g.Vertices)
+	for i := range dist {
+		dist[i] = 1<<31 - 1 // MaxInt
+	}
+
+	pq := make(PriorityQueue, 0)
+	heap.Init(&pq)
+	for _, source := range sources {
+		dist[source] = 0
+		heap.Push(&pq, &PQItem{Value: source, Priority: 0})
+	}
+
+	visited := make([]bool, g.Vertices)
+
+	for pq.Len() > 0 {
+		item := heap.Pop(&pq).(*PQItem)
+		u := item.Value.(int)
+
+		if visited[u] {
+			continue
+		}
+		visited[u] = true
+
+		for _, edge := range g.Edges[u] {
+			if visited[edge.To] {
+				continue
+			}
+			newDist := dist[u] + edge.Weight
+			if newDist < dist[edge.To] {
+				dist[edge.To] = newDist
+				heap.Push(&pq, &PQItem{Value: edge.To, Priority: newDist})
+			}
+		}
+	}
+
+	return dist
+}
+
+// DijkstraTo finds the shortest path distance from source to target,
+// returning as soon as target is finalized (popped from the queue)
+// instead of settling the whole graph. It returns ctx.Err() if the
+// context is cancelled mid-search and ErrNotFound if target is
+// unreachable.
+func (g *Graph) DijkstraTo(ctx context.Context, source, target int) (int, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	dist := make([]int, g.Vertices)
+	for i := range dist {
+		dist[i] = 1<<31 - 1 // MaxInt
+	}
+	dist[source] = 0
+
+	pq := make(PriorityQueue, 0)
+	heap.Init(&pq)
+	heap.Push(&pq, &PQItem{Value: source, Priority: 0})
+
+	visited := make([]bool, g.Vertices)
+
+	for pq.Len() > 0 {
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		default:
+		}
+
+		item := heap.Pop(&pq).(*PQItem)
+		u := item.Value.(int)
+
+		if u == target {
+			return dist[u], nil
+		}
+		if visited[u] {
+			continue
+		}
+		visited[u] = true
+
+		for _, edge := range g.Edges[u] {
+			if visited[edge.To] {
+				continue
+			}
+			newDist := dist[u] + edge.Weight
+			if newDist < dist[edge.To] {
+				dist[edge.To] = newDist
+				heap.Push(&pq, &PQItem{Value: edge.To, Priority: newDist})
+			}
+		}
+	}
+
+	return 0, ErrNotFound
+}
+
+// ShortestPath returns the cheapest path from source to target as a
+// vertex sequence along with its cost. The path is nil when target is
+// unreachable.
+func (g *Graph) ShortestPath(source, target int) ([]int, int) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.shortestPathExcluding(source, target, nil, nil)
+}
+
+// shortestPathExcluding runs Dijkstra with predecessor tracking,
+// skipping the given edges and vertices. Caller must hold g.mu.
+func (g *Graph) shortestPathExcluding(source, target int, removedEdges map[[2]int]bool, removedNodes []bool) ([]int, int) {
+	dist := make([]int, g.Vertices)
+	prev := make([]int, g.Vertices)
+	for i := range dist {
+		dist[i] = 1<<31 - 1 // MaxInt
+		prev[i] = -1
+	}
+	dist[source] = 0
+
+	pq := make(PriorityQueue, 0)
+	heap.Init(&pq)
+	heap.Push(&pq, &PQItem{Value: source, Priority: 0})
+
+	visited := make([]bool, g.Vertices)
+
+	for pq.Len() > 0 {
+		item := heap.Pop(&pq).(*PQItem)
+		u := item.Value.(int)
+
+		if u == target {
+			break
+		}
+		if visited[u] {
+			continue
+		}
+		visited[u] = true
+
+		for _, edge := range g.Edges[u] {
+			if visited[edge.To] {
+				continue
+			}
+			if removedNodes != nil && removedNodes[edge.To] {
+				continue
+			}
+			if removedEdges != nil && removedEdges[[2]int{u, edge.To}] {
+				continue
+			}
+			newDist := dist[u] + edge.Weight
+			if newDist < dist[edge.To] {
+				dist[edge.To] = newDist
+				prev[edge.To] = u
+				heap.Push(&pq, &PQItem{Value: edge.To, Priority: newDist})
+			}
+		}
+	}
+
+	if dist[target] == 1<<31-1 {
+		return nil, 0
+	}
+
+	var path []int
+	for v := target; v != -1; v = prev[v] {
+		path = append([]int{v}, path...)
+	}
+	return path, dist[target]
+}
+
+// KShortestPaths returns up to k loopless paths from source to target
+// sorted by increasing cost, along with their costs, using Yen's
+// algorithm on top of ShortestPath. Fewer than k paths are returned
+// when no more exist.
+func (g *Graph) KShortestPaths(source, target, k int) ([][]int, []int) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	best, cost := g.shortestPathExcluding(source, target, nil, nil)
+	if best == nil || k <= 0 {
+		return nil, nil
+	}
+
+	paths := [][]int{best}
+	costs := []int{cost}
+	var candidates [][]int
+	var candidateCosts []int
+
+	for len(paths) < k {
+		lastPath := paths[len(paths)-1]
+
+		for i := 0; i < len(lastPath)-1; i++ {
+			spurNode := lastPath[i]
+			rootPath := lastPath[:i+1]
+
+			// Remove edges used by already-found paths sharing this root.
+			removedEdges := make(map[[2]int]bool)
+			for _, p := range paths {
+				if len(p) > i && equalPaths(p[:i+1], rootPath) {
+					removedEdges[[2]int{p[i], p[i+1]}] = true
+				}
+			}
+
+			// Remove root path nodes except the spur node.
+			removedNodes := make([]bool, g.Vertices)
+			for _, v := range rootPath[:len(rootPath)-1] {
+				removedNodes[v] = true
+			}
+
+			spurPath, spurCost := g.shortestPathExcluding(spurNode, target, removedEdges, removedNodes)
+			if spurPath == nil {
+				continue
+			}
+
+			total := append(append([]int{}, rootPath[:len(rootPath)-1]...), spurPath...)
+			totalCost := spurCost
+			for j := 0; j < i; j++ {
+				totalCost += g.edgeWeight(lastPath[j], lastPath[j+1])
+			}
+
+			duplicate := false
+			for _, c := range candidates {
+				if equalPaths(c, total) {
+					duplicate = true
+					break
+				}
+			}
+			if !duplicate {
+				candidates = append(candidates, total)
+				candidateCosts = append(candidateCosts, totalCost)
+			}
+		}
+
+		if len(candidates) == 0 {
+			break
+		}
+
+		// Pop the cheapest candidate.
+		bestIdx := 0
+		for i, c := range candidateCosts {
+			if c < candidateCosts[bestIdx] {
+				bestIdx = i
+			}
+		}
+		paths = append(paths, candidates[bestIdx])
+		costs = append(costs, candidateCosts[bestIdx])
+		candidates = append(candidates[:bestIdx], candidates[bestIdx+1:]...)
+		candidateCosts = append(candidateCosts[:bestIdx], candidateCosts[bestIdx+1:]...)
+	}
+
+	return paths, costs
+}
+
+// edgeWeight returns the weight of the first edge from u to v.
+// Caller must hold g.mu.
+func (g *Graph) edgeWeight(u, v int) int {
+	for _, edge := range g.Edges[u] {
+		if edge.To == v {
+			return edge.Weight
+		}
+	}
+	return 0
+}
+
+// equalPaths reports whether two vertex sequences are identical.
+func equalPaths(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// BFS performs breadth-first search.
+func (g *Graph) BFS(start int) []int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	visited := make([]bool, g.Vertices)
+	queue := []int{start}
+	var result []int
+
+	for len(queue) > 0 {
+		u := queue[0]
+		queue = queue[1:]
+
+		if visited[u] {
+			continue
+		}
+		visited[u] = true
+		result = append(result, u)
+
+		for _, edge := range g.Edges[u] {
+			if !visited[edge.To] {
+				queue = append(queue, edge.To)
+			}
+		}
+	}
+
+	return result
+}
+
+// ShortestHops returns the number of edges on the shortest path from
+// start to each vertex, ignoring weights (-1 for unreachable). This is
+// the BFS answer to "degrees of separation", where Dijkstra would
+// minimize total weight instead.
+func (g *Graph) ShortestHops(start int) []int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	hops := make([]int, g.Vertices)
+	for i := range hops {
+		hops[i] = -1
+	}
+	hops[start] = 0
+	queue := []int{start}
+
+	for len(queue) > 0 {
+		u := queue[0]
+		queue = queue[1:]
+
+		for _, edge := range g.Edges[u] {
+			if hops[edge.To] == -1 {
+				hops[edge.To] = hops[u] + 1
+				queue = append(queue, edge.To)
+			}
+		}
+	}
+
+	return hops
+}
+
+// Reachable reports whether any path leads from one vertex to another,
+// stopping the search as soon as the target is seen instead of
+// finishing a full BFS. A vertex is always reachable from itself.
+func (g *Graph) Reachable(from, to int) bool {
+	if from == to {
+		return true
+	}
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	visited := make([]bool, g.Vertices)
+	visited[from] = true
+	queue := []int{from}
+
+	for len(queue) > 0 {
+		u := queue[0]
+		queue = queue[1:]
+
+		for _, edge := range g.Edges[u] {
+			if edge.To == to {
+				return true
+			}
+			if !visited[edge.To] {
+				visited[edge.To] = true
+				queue = append(queue, edge.To)
+			}
+		}
+	}
+
+	return false
+}
+
+// MaxFlow computes the maximum flow from source to sink using
+// Edmonds-Karp (BFS-based augmenting paths), treating edge weights as
+// capacities. Residual capacities are tracked in an internal matrix so
+// Edges is never mutated. Returns 0 when sink is unreachable or when
+// source and sink coincide, in which case no augmenting is meaningful.
+func (g *Graph) MaxFlow(source, sink int) int {
+	if source == sink {
+		return 0
+	}
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	// Build the residual capacity matrix; parallel edges accumulate.
+	residual := make([][]int, g.Vertices)
+	for i := range residual {
+		residual[i] = make([]int, g.Vertices)
+	}
+	for from, edges := range g.Edges {
+		for _, edge := range edges {
+			residual[from][edge.To] += edge.Weight
+		}
+	}
+
+	flow := 0
+	for {
+		// BFS for an augmenting path in the residual graph.
+		parent := make([]int, g.Vertices)
+		for i := range parent {
+			parent[i] = -1
+		}
+		parent[source] = source
+		queue := []int{source}
+		for len(queue) > 0 && parent[sink] == -1 {
+			u := queue[0]
+			queue = queue[1:]
+			for v := 0; v < g.Vertices; v++ {
+				if parent[v] == -1 && residual[u][v] > 0 {
+					parent[v] = u
+					queue = append(queue, v)
+				}
+			}
+		}
+		if parent[sink] == -1 {
+			break
+		}
+
+		// Find the bottleneck along the path.
+		bottleneck := 1<<31 - 1
+		for v := sink; v != source; v = parent[v] {
+			if residual[parent[v]][v] < bottleneck {
+				bottleneck = residual[parent[v]][v]
+			}
+		}
+
+		// Augment along the path.
+		for v := sink; v != source; v = parent[v] {
+			residual[parent[v]][v] -= bottleneck
+			residual[v][parent[v]] += bottleneck
+		}
+		flow += bottleneck
+	}
+
+	return flow
+}
+
+// DFS performs depth-first search.
+func (g *Graph) DFS(start int) []int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	visited := make([]bool, g.Vertices)
+	var result []int
+	g.dfsHelper(start, visited, &result)
+	return result
+}
+
+func (g *Graph) dfsHelper(u int, visited []bool, result *[]int) {
+	if visited[u] {
+		return
+	}
+	visited[u] = true
+	*result = append(*result, u)
+
+	for _, edge := range g.Edges[u] {
+		g.dfsHelper(edge.To, visited, result)
+	}
+}
+
+// Walk traverses the graph breadth-first from start, calling visit for
+// each node. Traversal halts immediately when visit returns false, so no
+// result slice is allocated for search-and-stop workloads.
+func (g *Graph) Walk(start int, visit func(node int) bool) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	visited := make([]bool, g.Vertices)
+	queue := []int{start}
+
+	for len(queue) > 0 {
+		u := queue[0]
+		queue = queue[1:]
+
+		if visited[u] {
+			continue
+		}
+		visited[u] = true
+
+		if !visit(u) {
+			return
+		}
+
+		for _, edge := range g.Edges[u] {
+			if !visited[edge.To] {
+				queue = append(queue, edge.To)
+			}
+		}
+	}
+}
+
+// DFSIterative performs depth-first search with an explicit stack,
+// avoiding the recursion depth limits of DFS. Neighbors are pushed in
+// reverse order so the visitation order matches the recursive DFS.
+func (g *Graph) DFSIterative(start int) []int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	visited := make([]bool, g.Vertices)
+	stack := []int{start}
+	var result []int
+
+	for len(stack) > 0 {
+		u := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if visited[u] {
+			continue
+		}
+		visited[u] = true
+		result = append(result, u)
+
+		edges := g.Edges[u]
+		for i := len(edges) - 1; i >= 0; i-- {
+			if !visited[edges[i].To] {
+				stack = append(stack, edges[i].To)
+			}
+		}
+	}
+
+	return result
+}
+
+// IsBipartite reports whether the graph is 2-colorable, treating edges
+// as undirected. It two-colors each component via BFS and returns false
+// when an odd cycle is found. Disconnected graphs and isolated vertices
+// are handled; an empty graph is trivially bipartite.
+func (g *Graph) IsBipartite() bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	// Build an undirected adjacency view.
+	adj := make([][]int, g.Vertices)
+	for from, edges := range g.Edges {
+		for _, edge := range edges {
+			adj[from] = append(adj[from], edge.To)
+			adj[edge.To] = append(adj[edge.To], from)
+		}
+	}
+
+	colors := make([]int, g.Vertices) // 0 = uncolored, 1 and 2 = colors
+	for start := 0; start < g.Vertices; start++ {
+		if colors[start] != 0 {
+			continue
+		}
+		colors[start] = 1
+		queue := []int{start}
+		for len(queue) > 0 {
+			u := queue[0]
+			queue = queue[1:]
+			for _, v := range adj[u] {
+				if colors[v] == 0 {
+					colors[v] = 3 - colors[u]
+					queue = append(queue, v)
+				} else if colors[v] == colors[u] {
+					return false
+				}
+			}
+		}
+	}
+
+	return true
+}
+
+// ===== LABELED GRAPH =====
+
+// LabeledGraph wraps Graph to allow string vertex names.
+type LabeledGraph struct {
+	graph *Graph
+	ids   map[string]int
+	names []string
+	mu    sync.RWMutex
+}
+
+// NewLabeledGraph creates a new labeled graph.
+func NewLabeledGraph() *LabeledGraph {
+	return &LabeledGraph{
+		graph: NewGraph(0),
+		ids:   make(map[string]int),
+	}
+}
+
+// id returns the dense int id for a name, assigning one if needed.
+// Caller must hold lg.mu.
+func (lg *LabeledGraph) id(name string) int {
+	if id, ok := lg.ids[name]; ok {
+		return id
+	}
+	id := len(lg.names)
+	lg.ids[name] = id
+	lg.names = append(lg.names, name)
+	lg.graph.Vertices = len(lg.names)
+	return id
+}
+
+// AddEdge adds a directed edge between named vertices.
+func (lg *LabeledGraph) AddEdge(from, to string, weight int) {
+	lg.mu.Lock()
+	defer lg.mu.Unlock()
+	lg.graph.AddEdge(lg.id(from), lg.id(to), weight)
+}
+
+// Dijkstra finds shortest path distances from source, keyed by vertex name.
+func (lg *LabeledGraph) Dijkstra(source string) map[string]int {
+	lg.mu.RLock()
+	defer lg.mu.RUnlock()
+
+	id, ok := lg.ids[source]
+	if !ok {
+		return nil
+	}
+	dist := lg.graph.Dijkstra(id)
+	result := make(map[string]int, len(dist))
+	for i, d := range dist {
+		result[lg.names[i]] = d
+	}
+	return result
+}
+
+// BFS performs breadth-first search, returning visited vertex names.
+func (lg *LabeledGraph) BFS(start string) []string {
+	lg.mu.RLock()
+	defer lg.mu.RUnlock()
+
+	id, ok := lg.ids[start]
+	if !ok {
+		return nil
+	}
+	order := lg.graph.BFS(id)
+	result := make([]string, len(order))
+	for i, u := range order {
+		result[i] = lg.names[u]
+	}
+	return result
+}
+
+// ===== SORTING =====
+
+// QuickSort sorts a slice in place.
+func QuickSort(arr []int) {
+	if len(arr) <= 1 {
+		return
+	}
+	quickSortHelper(arr, 0, len(arr)-1)
+}
+
+// insertionSortCutoff is the partition size below which quickSortHelper
+// switches to insertion sort.
+const insertionSortCutoff = 16
+
+func quickSortHelper(arr []int, low, high int) {
+	if low >= high {
+		return
+	}
+	if high-low+1 < insertionSortCutoff {
+		insertionSort(arr, low, high)
+		return
+	}
+	medianOfThree(arr, low, high)
+	p := partition(arr, low, high)
+	quickSortHelper(arr, low, p-1)
+	quickSortHelper(arr, p+1, high)
+}
+
+// insertionSort sorts arr[low..high] in place; fast for small ranges.
+func insertionSort(arr []int, low, high int) {
+	for i := low + 1; i <= high; i++ {
+		key := arr[i]
+		j := i - 1
+		for j >= low && arr[j] > key {
+			arr[j+1] = arr[j]
+			j--
+		}
+		arr[j+1] = key
+	}
+}
+
+// medianOfThree moves the median of arr[low], arr[mid] and arr[high]
+// into arr[high] so partition avoids the O(n^2) worst case on sorted
+// and reverse-sorted input.
+func medianOfThree(arr []int, low, high int) {
+	mid := low + (high-low)/2
+	if arr[mid] < arr[low] {
+		arr[mid], arr[low] = arr[low], arr[mid]
+	}
+	if arr[high] < arr[low] {
+		arr[high], arr[low] = arr[low], arr[high]
+	}
+	if arr[mid] < arr[high] {
+		arr[mid], arr[high] = arr[high], arr[mid]
+	}
+}
+
+func partition(arr []int, low, high int) int {
+	pivot := arr[high]
+	i := low - 1
+
+	for j := low; j < high; j++ {
+		if arr[j] < pivot {
+			i++
+			arr[i], arr[j] = arr[j], arr[i]
+		}
+	}
+	arr[i+1], arr[high] = arr[high], arr[i+1]
+	return i + 1
+}
+
+// QuickSortDesc sorts a slice in place in descending order.
+func QuickSortDesc(arr []int) {
+	SortFunc(arr, func(a, b int) bool { return a > b })
+}
+
+// MergeSortDesc returns a copy sorted in descending order. Like
+// MergeSort it is stable.
+func MergeSortDesc(arr []int) []int {
+	return MergeSortFunc(arr, func(a, b int) bool { return a > b })
+}
+
+// Sort sorts a slice in place, descending when desc is true.
+func Sort(arr []int, desc bool) {
+	if desc {
+		QuickSortDesc(arr)
+		return
+	}
+	QuickSort(arr)
+}
+
+// HeapSort sorts a slice in place using a binary max-heap. The heap is
+// built bottom-up, then the maximum is repeatedly swapped to the end.
+// Empty and single-element slices are returned unchanged.
+func HeapSort(arr []int) {
+	n := len(arr)
+	if n <= 1 {
+		return
+	}
+
+	// Build the max-heap bottom-up.
+	for i := n/2 - 1; i >= 0; i-- {
+		siftDown(arr, i, n)
+	}
+
+	// Repeatedly extract the maximum.
+	for end := n - 1; end > 0; end-- {
+		arr[0], arr[end] = arr[end], arr[0]
+		siftDown(arr, 0, end)
+	}
+}
+
+func siftDown(arr []int, root, end int) {
+	for {
+		child := 2*root + 1
+		if child >= end {
+			return
+		}
+		if child+1 < end && arr[child+1] > arr[child] {
+			child++
+		}
+		if arr[root] >= arr[child] {
+			return
+		}
+		arr[root], arr[child] = arr[child], arr[root]
+		root = child
+	}
+}
+
+// SortFunc sorts a slice in place using the given less comparator.
+// Like QuickSort it is quicksort-based and not stable.
+func SortFunc[T any](arr []T, less func(a, b T) bool) {
+	if len(arr) <= 1 {
+		return
+	}
+	sortFuncHelper(arr, 0, len(arr)-1, less)
+}
+
+func sortFuncHelper[T any](arr []T, low, high int, less func(a, b T) bool) {
+	if low < high {
+		p := partitionFunc(arr, low, high, less)
+		sortFuncHelper(arr, low, p-1, less)
+		sortFuncHelper(arr, p+1, high, less)
+	}
+}
+
+func partitionFunc[T any](arr []T, low, high int, less func(a, b T) bool) int {
+	pivot := arr[high]
+	i := low - 1
+
+	for j := low; j < high; j++ {
+		if less(arr[j], pivot) {
+			i++
+			arr[i], arr[j] = arr[j], arr[i]
+		}
+	}
+	arr[i+1], arr[high] = arr[high], arr[i+1]
+	return i + 1
+}
+
+// MergeSortFunc returns a sorted copy using the given less comparator.
+// The sort is stable: equal elements keep their input order.
+func MergeSortFunc[T any](arr []T, less func(a, b T) bool) []T {
+	if len(arr) <= 1 {
+		return arr
+	}
+
+	mid := len(arr) / 2
+	left := MergeSortFunc(arr[:mid], less)
+	right := MergeSortFunc(arr[mid:], less)
+
+	return mergeFunc(left, right, less)
+}
+
+func mergeFunc[T any](left, right []T, less func(a, b T) bool) []T {
+	result := make([]T, 0, len(left)+len(right))
+	i, j := 0, 0
+
+	for i < len(left) && j < len(right) {
+		if !less(right[j], left[i]) {
+			result = append(result, left[i])
+			i++
+		} else {
+			result = append(result, right[j])
+			j++
+		}
+	}
+
+	result = append(result, left[i:]...)
+	result = append(result, right[j:]...)
+	return result
+}
+
+// QuickSelect returns the k-th smallest element (0-based) in expected
+// O(n) time by partitioning in place, and false when k is out of range.
+// Note that the input slice may be reordered.
+func QuickSelect(arr []int, k int) (int, bool) {
+	if k < 0 || k >= len(arr) {
+		return 0, false
+	}
+
+	low, high := 0, len(arr)-1
+	for {
+		if low == high {
+			return arr[low], true
+		}
+		p := partition(arr, low, high)
+		switch {
+		case k == p:
+			return arr[p], true
+		case k < p:
+			high = p - 1
+		default:
+			low = p + 1
+		}
+	}
+}
+
+// MergeSort returns a sorted copy.
+func MergeSort(arr []int) []int {
+	if len(arr) <= 1 {
+		return arr
+	}
+
+	mid := len(arr) / 2
+	left := MergeSort(arr[:mid])
+	right := MergeSort(arr[mid:])
+
+	return merge(left, right)
+}
+
+func merge(left, right []int) []int {
+	result := make([]int, 0, len(left)+len(right))
+	i, j := 0, 0
+
+	for i < len(left) && j < len(right) {
+		if left[i] <= right[j] {
+			result = append(result, left[i])
+			i++
+		} else {
+			result = append(result, right[j])
+			j++
+		}
+	}
+
+	result = append(result, left[i:]...)
+	result = append(result, right[j:]...)
+	return result
+}
+
+// ===== DYNAMIC PROGRAMMING =====
+
+// LCS finds the longest common subsequence.
+func LCS(s1, s2 string) string {
+	m, n := len(s1), len(s2)
+	dp := make([][]int, m+1)
+	for i := range dp {
+		dp[i] = make([]int, n+1)
+	}
+
+	for i := 1; i <= m; i++ {
+		for j := 1; j <= n; j++ {
+			if s1[i-1] == s2[j-1] {
+				dp[i][j] = dp[i-1][j-1] + 1
+			} else {
+				if dp[i-1][j] > dp[i][j-1] {
+					dp[i][j] = dp[i-1][j]
+				} else {
+					dp[i][j] = dp[i][j-1]
+				}
+			}
+		}
+	}
+
+	// Backtrack
+	lcs := make([]byte, 0, dp[m][n])
+	i, j := m, n
+	for i > 0 && j > 0 {
+		if s1[i-1] == s2[j-1] {
+			lcs = append([]byte{s1[i-1]}, lcs...)
+			i--
+			j--
+		} else if dp[i-1][j] > dp[i][j-1] {
+			i--
+		} else {
+			j--
+		}
+	}
+
+	return string(lcs)
+}
+
+// LongestIncreasingSubsequence returns one strictly increasing
+// subsequence of maximum length using patience sorting in O(n log n),
+// with predecessor tracking for reconstruction.
+func LongestIncreasingSubsequence(arr []int) []int {
+	if len(arr) == 0 {
+		return nil
+	}
+
+	// tails[k] is the index of the smallest tail of an increasing
+	// subsequence of length k+1.
+	tails := make([]int, 0, len(arr))
+	prev := make([]int, len(arr))
+
+	for i, v := range arr {
+		// Binary search for the first tail >= v (strictly increasing).
+		lo, hi := 0, len(tails)
+		for lo < hi {
+			mid := (lo + hi) / 2
+			if arr[tails[mid]] < v {
+				lo = mid + 1
+			} else {
+				hi = mid
+			}
+		}
+
+		if lo > 0 {
+			prev[i] = tails[lo-1]
+		} else {
+			prev[i] = -1
+		}
+		if lo == len(tails) {
+			tails = append(tails, i)
+		} else {
+			tails[lo] = i
+		}
+	}
+
+	// Walk predecessors back from the best tail.
+	result := make([]int, len(tails))
+	for i, j := len(tails)-1, tails[len(tails)-1]; i >= 0; i-- {
+		result[i] = arr[j]
+		j = prev[j]
+	}
+	return result
+}
+
+// EditDistance computes Levenshtein distance.
+func EditDistance(s1, s2 string) int {
+	m, n := len(s1), len(s2)
+	dp := make([][]int, m+1)
+	for i := range dp {
+		dp[i] = make([]int, n+1)
+	}
+
+	for i := 0; i <= m; i++ {
+		dp[i][0] = i
+	}
+	for j := 0; j <= n; j++ {
+		dp[0][j] = j
+	}
+
+	for i := 1; i <= m; i++ {
+		for j := 1; j <= n; j++ {
+			if s1[i-1] == s2[j-1] {
+				dp[i][j] = dp[i-1][j-1]
+			} else {
+				min := dp[i-1][j]
+				if dp[i][j-1] < min {
+					min = dp[i][j-1]
+				}
+				if dp[i-1][j-1] < min {
+					min = dp[i-1][j-1]
+				}
+				dp[i][j] = 1 + min
+			}
+		}
+	}
+
+	return dp[m][n]
+}
+
+// MaxSubarraySum returns the maximum contiguous sum and the inclusive
+// index range achieving it, using Kadane's algorithm. All-negative
+// input yields the single largest element; empty input returns
+// (0, -1, -1).
+func MaxSubarraySum(arr []int) (sum int, start int, end int) {
+	if len(arr) == 0 {
+		return 0, -1, -1
+	}
+
+	sum, start, end = arr[0], 0, 0
+	current, currentStart := arr[0], 0
+
+	for i := 1; i < len(arr); i++ {
+		if current < 0 {
+			current = arr[i]
+			currentStart = i
+		} else {
+			current += arr[i]
+		}
+		if current > sum {
+			sum = current
+			start = currentStart
+			end = i
+		}
+	}
+
+	return sum, start, end
+}
+
+// LCSMultiple returns a common subsequence of all input strings by
+// pairwise reduction with LCS. This is a heuristic: folding left to
+// right is not guaranteed to find the longest common subsequence of
+// all strings, but it is cheap and works well for small counts.
+func LCSMultiple(strs ...string) string {
+	if len(strs) == 0 {
+		return ""
+	}
+
+	result := strs[0]
+	for _, s := range strs[1:] {
+		result = LCS(result, s)
+		if result == "" {
+			return ""
+		}
+	}
+	return result
+}
+
+// WeightedEditDistance computes edit distance with custom per-operation
+// costs. With all costs 1 it equals EditDistance; a cheap substitution
+// cost makes replacing preferable to an insert plus a delete.
+func WeightedEditDistance(s1, s2 string, insertCost, deleteCost, substituteCost int) int {
+	m, n := len(s1), len(s2)
+	dp := make([][]int, m+1)
+	for i := range dp {
+		dp[i] = make([]int, n+1)
+	}
+
+	for i := 1; i <= m; i++ {
+		dp[i][0] = dp[i-1][0] + deleteCost
+	}
+	for j := 1; j <= n; j++ {
+		dp[0][j] = dp[0][j-1] + insertCost
+	}
+
+	for i := 1; i <= m; i++ {
+		for j := 1; j <= n; j++ {
+			if s1[i-1] == s2[j-1] {
+				dp[i][j] = dp[i-1][j-1]
+			} else {
+				min := dp[i-1][j] + deleteCost
+				if cost := dp[i][j-1] + insertCost; cost < min {
+					min = cost
+				}
+				if cost := dp[i-1][j-1] + substituteCost; cost < min {
+					min = cost
+				}
+				dp[i][j] = min
+			}
+		}
+	}
+
+	return dp[m][n]
+}
+
+// Knapsack solves the 0/1 knapsack problem.
+func Knapsack(weights, values []int, capacity int) int {
+	n := len(weights)
+	dp := make([][]int, n+1)
+	for i := range dp {
+		dp[i] = make([]int, capacity+1)
+	}
+
+	for i := 1; i <= n; i++ {
+		for w := 0; w <= capacity; w++ {
+			if weights[i-1] <= w {
+				include := dp[i-1][w-weights[i-1]] + values[i-1]
+				exclude := dp[i-1][w]
+				if include > exclude {
+					dp[i][w] = include
+				} else {
+					dp[i][w] = exclude
+				}
+			} else {
+				dp[i][w] = dp[i-1][w]
+			}
+		}
+	}
+
+	return dp[n][capacity]
+}
+
+// SubsetSum returns a subset of nums summing to target and whether one
+// exists, using DP over reachable sums with per-sum backtracking.
+// Target 0 yields the empty subset; duplicate values are handled since
+// each element is considered exactly once.
+func SubsetSum(nums []int, target int) ([]int, bool) {
+	if target == 0 {
+		return []int{}, true
+	}
+	if target < 0 {
+		return nil, false
+	}
+
+	// usedItem[s] is the index of the element that first reached sum s;
+	// -1 marks unreached sums.
+	usedItem := make([]int, target+1)
+	for s := range usedItem {
+		usedItem[s] = -1
+	}
+
+	for i, v := range nums {
+		if v < 0 || v > target {
+			continue
+		}
+		// Walk sums downward so each element is used at most once.
+		for s := target; s >= v; s-- {
+			if usedItem[s] == -1 && (s == v || (usedItem[s-v] != -1 && usedItem[s-v] < i)) {
+				usedItem[s] = i
+			}
+		}
+		if usedItem[target] != -1 {
+			break
+		}
+	}
+
+	if usedItem[target] == -1 {
+		return nil, false
+	}
+
+	var subset []int
+	for s := target; s > 0; {
+		v := nums[usedItem[s]]
+		subset = append([]int{v}, subset...)
+		s -= v
+	}
+	return subset, true
+}
+
+// KnapsackOptimized solves the 0/1 knapsack problem with a single
+// rolling array, cutting memory from O(n*capacity) to O(capacity). The
+// array is iterated from high capacity to low so each item is counted
+// at most once. The result matches Knapsack.
+func KnapsackOptimized(weights, values []int, capacity int) int {
+	dp := make([]int, capacity+1)
+
+	for i := 0; i < len(weights); i++ {
+		for w := capacity; w >= weights[i]; w-- {
+			candidate := dp[w-weights[i]] + values[i]
+			if candidate > dp[w] {
+				dp[w] = candidate
+			}
+		}
+	}
+
+	return dp[capacity]
+}
+
+// KnapsackItems solves the 0/1 knapsack problem and returns the best
+// value plus the indices of the chosen items, reconstructed by
+// backtracking through the DP table. It returns (0, nil) when weights
+// and values differ in length.
+func KnapsackItems(weights, values []int, capacity int) (int, []int) {
+	if len(weights) != len(values) {
+		return 0, nil
+	}
+
+	n := len(weights)
+	dp := make([][]int, n+1)
+	for i := range dp {
+		dp[i] = make([]int, capacity+1)
+	}
+
+	for i := 1; i <= n; i++ {
+		for w := 0; w <= capacity; w++ {
+			if weights[i-1] <= w {
+				include := dp[i-1][w-weights[i-1]] + values[i-1]
+				exclude := dp[i-1][w]
+				if include > exclude {
+					dp[i][w] = include
+				} else {
+					dp[i][w] = exclude
+				}
+			} else {
+				dp[i][w] = dp[i-1][w]
+			}
+		}
+	}
+
+	// Backtrack: an item was taken where the value differs from the row
+	// above.
+	var items []int
+	w := capacity
+	for i := n; i > 0; i-- {
+		if dp[i][w] != dp[i-1][w] {
+			items = append([]int{i - 1}, items...)
+			w -= weights[i-1]
+		}
+	}
+
+	return dp[n][capacity], items
+}
+
+// ErrNotFound is returned when an item is not found.
+var ErrNotFound = errors.New("item not found")
@@ -0,0 +1,607 @@
+package algo
+
+import (
+	"container/heap"
+	"context"
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestBSTInsertFindDelete(t *testing.T) {
+	bst := &BST{}
+	for _, v := range []int{5, 3, 8, 1, 4, 7, 9} {
+		bst.Insert(v)
+	}
+	if !bst.Find(4) || bst.Find(6) {
+		t.Fatal("Find gave wrong membership")
+	}
+	if !bst.Delete(3) {
+		t.Fatal("Delete(3) should succeed")
+	}
+	if bst.Delete(3) {
+		t.Fatal("second Delete(3) should fail")
+	}
+	want := []int{1, 4, 5, 7, 8, 9}
+	if got := bst.InOrder(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("InOrder = %v, want %v", got, want)
+	}
+}
+
+func TestBSTMerge(t *testing.T) {
+	a, b := &BST{}, &BST{}
+	for _, v := range []int{1, 3, 5} {
+		a.Insert(v)
+	}
+	for _, v := range []int{2, 3, 4} {
+		b.Insert(v)
+	}
+	a.Merge(b)
+	want := []int{1, 2, 3, 4, 5}
+	if got := a.InOrder(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("merged InOrder = %v, want %v", got, want)
+	}
+	// Merging with nil or self is a no-op.
+	a.Merge(nil)
+	a.Merge(a)
+	if got := a.InOrder(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("after no-op merges InOrder = %v, want %v", got, want)
+	}
+}
+
+func TestBSTMergeConcurrentNoDeadlock(t *testing.T) {
+	a, b := &BST{}, &BST{}
+	for i := 0; i < 200; i += 2 {
+		a.Insert(i)
+		b.Insert(i + 1)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() { defer wg.Done(); a.Merge(b) }()
+		go func() { defer wg.Done(); b.Merge(a) }()
+	}
+	wg.Wait()
+	vals := a.InOrder()
+	for i := 1; i < len(vals); i++ {
+		if vals[i-1] >= vals[i] {
+			t.Fatalf("InOrder not strictly sorted at %d: %v, %v", i, vals[i-1], vals[i])
+		}
+	}
+}
+
+func TestNewPriorityQueueFrom(t *testing.T) {
+	items := []*PQItem{
+		{Value: "c", Priority: 3},
+		{Value: "a", Priority: 1},
+		{Value: "b", Priority: 2},
+	}
+	pq := NewPriorityQueueFrom(items)
+	var got []string
+	for pq.Len() > 0 {
+		got = append(got, heap.Pop(pq).(*PQItem).Value.(string))
+	}
+	if !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+		t.Fatalf("pop order = %v", got)
+	}
+	// The input slice must not have been consumed.
+	if len(items) != 3 {
+		t.Fatalf("input slice mutated: %v", items)
+	}
+}
+
+func buildDiamond() *Graph {
+	// 0 -> 1 -> 3, 0 -> 2 -> 3 with a cheaper lower path.
+	g := NewGraph(4)
+	g.AddEdge(0, 1, 1)
+	g.AddEdge(1, 3, 5)
+	g.AddEdge(0, 2, 2)
+	g.AddEdge(2, 3, 1)
+	return g
+}
+
+func TestDijkstra(t *testing.T) {
+	g := buildDiamond()
+	dist := g.Dijkstra(0)
+	want := []int{0, 1, 2, 3}
+	if !reflect.DeepEqual(dist, want) {
+		t.Fatalf("Dijkstra = %v, want %v", dist, want)
+	}
+}
+
+func TestDijkstraMulti(t *testing.T) {
+	g := NewGraph(4)
+	g.AddEdge(0, 1, 10)
+	g.AddEdge(2, 1, 1)
+	g.AddEdge(2, 3, 4)
+	dist := g.DijkstraMulti([]int{0, 2})
+	want := []int{0, 1, 0, 4}
+	if !reflect.DeepEqual(dist, want) {
+		t.Fatalf("DijkstraMulti = %v, want %v", dist, want)
+	}
+	maxInt := 1<<31 - 1
+	for i, d := range g.DijkstraMulti(nil) {
+		if d != maxInt {
+			t.Fatalf("empty sources: dist[%d] = %d, want MaxInt", i, d)
+		}
+	}
+}
+
+func TestShortestHops(t *testing.T) {
+	g := buildDiamond()
+	g.AddVertex() // unreachable vertex 4
+	hops := g.ShortestHops(0)
+	want := []int{0, 1, 1, 2, -1}
+	if !reflect.DeepEqual(hops, want) {
+		t.Fatalf("ShortestHops = %v, want %v", hops, want)
+	}
+}
+
+func TestReachable(t *testing.T) {
+	g := buildDiamond()
+	g.AddVertex()
+	if !g.Reachable(0, 3) {
+		t.Fatal("0 should reach 3")
+	}
+	if g.Reachable(3, 0) {
+		t.Fatal("3 should not reach 0")
+	}
+	if !g.Reachable(2, 2) {
+		t.Fatal("a vertex reaches itself")
+	}
+	if g.Reachable(0, 4) {
+		t.Fatal("4 is unreachable")
+	}
+}
+
+func TestAddOrUpdateEdge(t *testing.T) {
+	g := NewGraph(2)
+	g.AddOrUpdateEdge(0, 1, 5)
+	g.AddOrUpdateEdge(0, 1, 7)
+	if n := len(g.Neighbors(0)); n != 1 {
+		t.Fatalf("expected 1 edge after update, got %d", n)
+	}
+	if w := g.Neighbors(0)[0].Weight; w != 7 {
+		t.Fatalf("weight = %d, want 7", w)
+	}
+}
+
+func TestGraphJSONRoundTrip(t *testing.T) {
+	g := buildDiamond()
+	data, err := json.Marshal(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var back Graph
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(back.Dijkstra(0), g.Dijkstra(0)) {
+		t.Fatal("round-tripped graph differs")
+	}
+}
+
+func TestMatrixRoundTrip(t *testing.T) {
+	g := buildDiamond()
+	back := GraphFromMatrix(g.ToMatrix())
+	if !reflect.DeepEqual(back.Dijkstra(0), g.Dijkstra(0)) {
+		t.Fatal("matrix round-tripped graph differs")
+	}
+}
+
+func TestMaxFlow(t *testing.T) {
+	g := NewGraph(4)
+	g.AddEdge(0, 1, 3)
+	g.AddEdge(0, 2, 2)
+	g.AddEdge(1, 3, 2)
+	g.AddEdge(2, 3, 3)
+	if got := g.MaxFlow(0, 3); got != 4 {
+		t.Fatalf("MaxFlow = %d, want 4", got)
+	}
+	if got := g.MaxFlow(3, 0); got != 0 {
+		t.Fatalf("reverse MaxFlow = %d, want 0", got)
+	}
+	// Degenerate source == sink must terminate and carry no flow.
+	if got := g.MaxFlow(1, 1); got != 0 {
+		t.Fatalf("MaxFlow(1,1) = %d, want 0", got)
+	}
+}
+
+func TestIsBipartite(t *testing.T) {
+	square := NewGraph(4)
+	for _, e := range [][2]int{{0, 1}, {1, 2}, {2, 3}, {3, 0}} {
+		square.AddEdge(e[0], e[1], 1)
+		square.AddEdge(e[1], e[0], 1)
+	}
+	if !square.IsBipartite() {
+		t.Fatal("even cycle should be bipartite")
+	}
+	triangle := NewGraph(3)
+	for _, e := range [][2]int{{0, 1}, {1, 2}, {2, 0}} {
+		triangle.AddEdge(e[0], e[1], 1)
+		triangle.AddEdge(e[1], e[0], 1)
+	}
+	if triangle.IsBipartite() {
+		t.Fatal("odd cycle should not be bipartite")
+	}
+}
+
+func TestSorts(t *testing.T) {
+	in := []int{5, 2, 9, 1, 5, 6}
+	asc := []int{1, 2, 5, 5, 6, 9}
+	desc := []int{9, 6, 5, 5, 2, 1}
+
+	qs := append([]int(nil), in...)
+	QuickSort(qs)
+	if !reflect.DeepEqual(qs, asc) {
+		t.Fatalf("QuickSort = %v", qs)
+	}
+	if got := MergeSort(append([]int(nil), in...)); !reflect.DeepEqual(got, asc) {
+		t.Fatalf("MergeSort = %v", got)
+	}
+	hs := append([]int(nil), in...)
+	HeapSort(hs)
+	if !reflect.DeepEqual(hs, asc) {
+		t.Fatalf("HeapSort = %v", hs)
+	}
+	sd := append([]int(nil), in...)
+	Sort(sd, true)
+	if !reflect.DeepEqual(sd, desc) {
+		t.Fatalf("Sort desc = %v", sd)
+	}
+}
+
+func TestSortFunc(t *testing.T) {
+	words := []string{"pear", "fig", "apple"}
+	SortFunc(words, func(a, b string) bool { return a < b })
+	if !reflect.DeepEqual(words, []string{"apple", "fig", "pear"}) {
+		t.Fatalf("SortFunc = %v", words)
+	}
+}
+
+func TestQuickSelect(t *testing.T) {
+	arr := []int{7, 1, 4, 9, 3}
+	for k, want := range map[int]int{0: 1, 2: 4, 4: 9} {
+		got, ok := QuickSelect(append([]int(nil), arr...), k)
+		if !ok || got != want {
+			t.Fatalf("QuickSelect(k=%d) = %d,%v, want %d", k, got, ok, want)
+		}
+	}
+	if _, ok := QuickSelect(arr, 99); ok {
+		t.Fatal("out-of-range k should report !ok")
+	}
+}
+
+func TestDynamicProgramming(t *testing.T) {
+	if got := LCS("ABCBDAB", "BDCABA"); len(got) != 4 {
+		t.Fatalf("LCS length = %d (%q), want 4", len(got), got)
+	}
+	if got := EditDistance("kitten", "sitting"); got != 3 {
+		t.Fatalf("EditDistance = %d, want 3", got)
+	}
+	lis := LongestIncreasingSubsequence([]int{10, 9, 2, 5, 3, 7, 101, 18})
+	if len(lis) != 4 {
+		t.Fatalf("LIS = %v, want length 4", lis)
+	}
+	sum, start, end := MaxSubarraySum([]int{-2, 1, -3, 4, -1, 2, 1, -5, 4})
+	if sum != 6 || start != 3 || end != 6 {
+		t.Fatalf("MaxSubarraySum = %d [%d,%d], want 6 [3,6]", sum, start, end)
+	}
+	if got := Knapsack([]int{1, 3, 4, 5}, []int{1, 4, 5, 7}, 7); got != 9 {
+		t.Fatalf("Knapsack = %d, want 9", got)
+	}
+	subset, ok := SubsetSum([]int{3, 34, 4, 12, 5, 2}, 9)
+	if !ok {
+		t.Fatal("SubsetSum should find a subset summing to 9")
+	}
+	total := 0
+	for _, v := range subset {
+		total += v
+	}
+	if total != 9 {
+		t.Fatalf("subset %v sums to %d, want 9", subset, total)
+	}
+}
+
+func TestLabeledGraph(t *testing.T) {
+	lg := NewLabeledGraph()
+	lg.AddEdge("a", "b", 1)
+	lg.AddEdge("b", "c", 2)
+	dist := lg.Dijkstra("a")
+	if dist["c"] != 3 {
+		t.Fatalf(`dist["c"] = %d, want 3`, dist["c"])
+	}
+	order := lg.BFS("a")
+	if !reflect.DeepEqual(order, []string{"a", "b", "c"}) {
+		t.Fatalf("BFS = %v", order)
+	}
+}
+
+func TestDFSVariantsAgree(t *testing.T) {
+	g := buildDiamond()
+	if got, want := g.DFSIterative(0), g.DFS(0); !reflect.DeepEqual(got, want) {
+		t.Fatalf("DFSIterative = %v, DFS = %v", got, want)
+	}
+}
+
+func TestWalkEarlyExit(t *testing.T) {
+	g := NewGraph(4)
+	g.AddEdge(0, 1, 1)
+	g.AddEdge(1, 2, 1)
+	g.AddEdge(2, 3, 1)
+
+	var visited []int
+	g.Walk(0, func(v int) bool {
+		visited = append(visited, v)
+		return len(visited) < 2
+	})
+	if !reflect.DeepEqual(visited, []int{0, 1}) {
+		t.Fatalf("visited = %v, want traversal to stop after callback returns false", visited)
+	}
+
+	// A callback that never declines sees the whole component.
+	count := 0
+	g.Walk(0, func(int) bool { count++; return true })
+	if count != 4 {
+		t.Fatalf("full walk visited %d vertices, want 4", count)
+	}
+}
+
+func TestDijkstraTo(t *testing.T) {
+	g := buildDiamond()
+	dist, err := g.DijkstraTo(context.Background(), 0, 3)
+	if err != nil || dist != 3 {
+		t.Fatalf("DijkstraTo = %d, %v; want 3", dist, err)
+	}
+	// Early exit agrees with the full run for every target.
+	full := g.Dijkstra(0)
+	for target, want := range full {
+		got, err := g.DijkstraTo(context.Background(), 0, target)
+		if err != nil || got != want {
+			t.Fatalf("DijkstraTo(0, %d) = %d, %v; want %d", target, got, err, want)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := g.DijkstraTo(ctx, 0, 3); !errors.Is(err, context.Canceled) {
+		t.Fatalf("cancelled context err = %v", err)
+	}
+
+	// Vertex 3 has no outgoing edges, so nothing is reachable from it.
+	if _, err := g.DijkstraTo(context.Background(), 3, 0); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("unreachable err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestShortestPathAndKShortestPaths(t *testing.T) {
+	// Three vertex-disjoint routes from 0 to 4 with costs 2, 4 and 6.
+	g := NewGraph(5)
+	g.AddEdge(0, 1, 1)
+	g.AddEdge(1, 4, 1)
+	g.AddEdge(0, 2, 2)
+	g.AddEdge(2, 4, 2)
+	g.AddEdge(0, 3, 3)
+	g.AddEdge(3, 4, 3)
+
+	path, cost := g.ShortestPath(0, 4)
+	if !reflect.DeepEqual(path, []int{0, 1, 4}) || cost != 2 {
+		t.Fatalf("ShortestPath = %v, %d; want [0 1 4], 2", path, cost)
+	}
+
+	paths, costs := g.KShortestPaths(0, 4, 3)
+	wantPaths := [][]int{{0, 1, 4}, {0, 2, 4}, {0, 3, 4}}
+	wantCosts := []int{2, 4, 6}
+	if !reflect.DeepEqual(paths, wantPaths) || !reflect.DeepEqual(costs, wantCosts) {
+		t.Fatalf("KShortestPaths = %v, %v; want %v, %v", paths, costs, wantPaths, wantCosts)
+	}
+
+	// Asking for more paths than exist returns only the existing ones.
+	paths, _ = g.KShortestPaths(0, 4, 10)
+	if len(paths) != 3 {
+		t.Fatalf("k=10 returned %d paths, want 3", len(paths))
+	}
+
+	// Unreachable targets: vertex 4 has no outgoing edges.
+	if path, _ := g.ShortestPath(4, 0); path != nil {
+		t.Fatalf("unreachable ShortestPath = %v, want nil", path)
+	}
+	if paths, costs := g.KShortestPaths(4, 0, 2); paths != nil || costs != nil {
+		t.Fatalf("unreachable KShortestPaths = %v, %v; want nil, nil", paths, costs)
+	}
+}
+
+func TestMergeSortFuncStable(t *testing.T) {
+	type pair struct{ key, seq int }
+	in := []pair{{2, 0}, {1, 1}, {2, 2}, {1, 3}, {2, 4}, {1, 5}}
+	got := MergeSortFunc(in, func(a, b pair) bool { return a.key < b.key })
+	want := []pair{{1, 1}, {1, 3}, {1, 5}, {2, 0}, {2, 2}, {2, 4}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("MergeSortFunc = %v, want equal keys in input order %v", got, want)
+	}
+}
+
+func TestKnapsackItems(t *testing.T) {
+	weights := []int{1, 3, 4, 5}
+	values := []int{1, 4, 5, 7}
+	const capacity = 7
+
+	best, items := KnapsackItems(weights, values, capacity)
+	if want := Knapsack(weights, values, capacity); best != want {
+		t.Fatalf("KnapsackItems value = %d, want %d", best, want)
+	}
+	totalWeight, totalValue := 0, 0
+	for _, i := range items {
+		totalWeight += weights[i]
+		totalValue += values[i]
+	}
+	if totalWeight > capacity {
+		t.Fatalf("selected weight %d exceeds capacity %d", totalWeight, capacity)
+	}
+	if totalValue != best {
+		t.Fatalf("selected values sum to %d, reported %d", totalValue, best)
+	}
+
+	if best, items := KnapsackItems([]int{1, 2}, []int{1}, 10); best != 0 || items != nil {
+		t.Fatalf("mismatched lengths = %d, %v; want 0, nil", best, items)
+	}
+}
+
+func TestKnapsackOptimizedMatchesKnapsack(t *testing.T) {
+	cases := []struct {
+		weights, values []int
+		capacity        int
+	}{
+		{[]int{1, 3, 4, 5}, []int{1, 4, 5, 7}, 7},
+		{[]int{2, 2, 2}, []int{3, 3, 3}, 1}, // nothing fits
+		{nil, nil, 10},
+		{[]int{5}, []int{9}, 5},
+	}
+	rng := rand.New(rand.NewSource(42))
+	for i := 0; i < 5; i++ {
+		n := 1 + rng.Intn(12)
+		weights := make([]int, n)
+		values := make([]int, n)
+		for j := range weights {
+			weights[j] = 1 + rng.Intn(10)
+			values[j] = 1 + rng.Intn(20)
+		}
+		cases = append(cases, struct {
+			weights, values []int
+			capacity        int
+		}{weights, values, rng.Intn(30)})
+	}
+	for _, c := range cases {
+		want := Knapsack(c.weights, c.values, c.capacity)
+		if got := KnapsackOptimized(c.weights, c.values, c.capacity); got != want {
+			t.Fatalf("KnapsackOptimized(%v, %v, %d) = %d, want %d",
+				c.weights, c.values, c.capacity, got, want)
+		}
+	}
+}
+
+func benchmarkKnapsackInput() ([]int, []int, int) {
+	rng := rand.New(rand.NewSource(7))
+	weights := make([]int, 50)
+	values := make([]int, 50)
+	for i := range weights {
+		weights[i] = 1 + rng.Intn(20)
+		values[i] = 1 + rng.Intn(50)
+	}
+	return weights, values, 200
+}
+
+func BenchmarkKnapsack(b *testing.B) {
+	weights, values, capacity := benchmarkKnapsackInput()
+	for i := 0; i < b.N; i++ {
+		Knapsack(weights, values, capacity)
+	}
+}
+
+func BenchmarkKnapsackOptimized(b *testing.B) {
+	weights, values, capacity := benchmarkKnapsackInput()
+	for i := 0; i < b.N; i++ {
+		KnapsackOptimized(weights, values, capacity)
+	}
+}
+
+func TestWeightedEditDistance(t *testing.T) {
+	// With unit costs the weighted form reduces to EditDistance.
+	for _, c := range [][2]string{{"kitten", "sitting"}, {"", "abc"}, {"same", "same"}} {
+		want := EditDistance(c[0], c[1])
+		if got := WeightedEditDistance(c[0], c[1], 1, 1, 1); got != want {
+			t.Fatalf("WeightedEditDistance(%q, %q, 1,1,1) = %d, want %d", c[0], c[1], got, want)
+		}
+	}
+
+	// Cheap substitution: one substitute (cost 1) beats delete+insert
+	// (cost 10).
+	if got := WeightedEditDistance("abc", "abd", 5, 5, 1); got != 1 {
+		t.Fatalf("cheap substitution = %d, want 1", got)
+	}
+	// Expensive substitution: delete+insert (cost 2) beats one
+	// substitute (cost 9).
+	if got := WeightedEditDistance("abc", "abd", 1, 1, 9); got != 2 {
+		t.Fatalf("expensive substitution = %d, want 2", got)
+	}
+}
+
+func TestLCSMultiple(t *testing.T) {
+	if got := LCSMultiple("abcde", "abde", "abe"); got != "abe" {
+		t.Fatalf("LCSMultiple = %q, want %q", got, "abe")
+	}
+	// No arguments and no common subsequence both yield the empty
+	// string, the latter without visiting the remaining inputs.
+	if got := LCSMultiple(); got != "" {
+		t.Fatalf("empty varargs = %q, want \"\"", got)
+	}
+	if got := LCSMultiple("abc", "xyz", "anything"); got != "" {
+		t.Fatalf("disjoint inputs = %q, want \"\"", got)
+	}
+	// A single argument is its own LCS.
+	if got := LCSMultiple("solo"); got != "solo" {
+		t.Fatalf("single input = %q, want %q", got, "solo")
+	}
+}
+
+func TestQuickSortAdversarialInputs(t *testing.T) {
+	// Sorted and reverse-sorted inputs are the classic quadratic
+	// killers for a naive last-element pivot; the median-of-three
+	// hybrid must handle 10k elements without degrading.
+	const n = 10000
+	sorted := make([]int, n)
+	reversed := make([]int, n)
+	for i := 0; i < n; i++ {
+		sorted[i] = i
+		reversed[i] = n - i
+	}
+	for name, in := range map[string][]int{"sorted": sorted, "reversed": reversed} {
+		got := append([]int(nil), in...)
+		QuickSort(got)
+		for i := 1; i < len(got); i++ {
+			if got[i-1] > got[i] {
+				t.Fatalf("%s input: out of order at %d", name, i)
+			}
+		}
+	}
+}
+
+func TestDFSIterativeDeepChain(t *testing.T) {
+	// A 100k-vertex linear chain would overflow the stack under the
+	// recursive DFS; the explicit-stack variant must walk it in order.
+	const n = 100000
+	g := NewGraph(n)
+	for i := 0; i < n-1; i++ {
+		g.AddEdge(i, i+1, 1)
+	}
+	order := g.DFSIterative(0)
+	if len(order) != n {
+		t.Fatalf("visited %d vertices, want %d", len(order), n)
+	}
+	for i, v := range order {
+		if v != i {
+			t.Fatalf("order[%d] = %d, want %d", i, v, i)
+		}
+	}
+}
+
+func TestDegrees(t *testing.T) {
+	g := NewGraph(4)
+	g.AddEdge(0, 1, 1)
+	g.AddEdge(0, 2, 1)
+	g.AddEdge(3, 1, 1)
+
+	if got := g.OutDegree(0); got != 2 {
+		t.Fatalf("OutDegree(0) = %d, want 2", got)
+	}
+	// Vertex 1 is only ever a target.
+	if out, in := g.OutDegree(1), g.InDegree(1); out != 0 || in != 2 {
+		t.Fatalf("vertex 1 degrees = out %d, in %d; want 0, 2", out, in)
+	}
+	if got := g.InDegree(0); got != 0 {
+		t.Fatalf("InDegree(0) = %d, want 0", got)
+	}
+}
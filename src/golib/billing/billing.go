@@ -0,0 +1,314 @@
+package billing
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// OrderItem represents an item in an order. Currency is optional; the
+// single-currency CalculateOrderTotal ignores it.
+type OrderItem struct {
+	Price     float64
+	Quantity  int
+	Discount  float64
+	Name      string
+	Currency  string
+	TaxExempt bool
+}
+
+// Order represents a complete order.
+type Order struct {
+	ID           string
+	CustomerName string
+	Date         string
+	Items        []OrderItem
+	Subtotal     float64
+	Shipping     float64
+	Tax          float64
+	Total        float64
+}
+
+// LineItem is the shared billing line used by both the Order and
+// Invoice flows, which previously duplicated identical math over
+// separate item structs.
+type LineItem struct {
+	Price    float64
+	Quantity int
+	Discount float64
+	Name     string
+}
+
+// RoundingMode selects how money amounts are rounded to cents.
+type RoundingMode int
+
+const (
+	// RoundHalfUp rounds half-cents away from zero (the historical
+	// behavior of the total and tax functions).
+	RoundHalfUp RoundingMode = iota
+	// RoundHalfEven rounds half-cents to the nearest even cent
+	// (banker's rounding).
+	RoundHalfEven
+	// RoundDown truncates toward zero.
+	RoundDown
+)
+
+// RoundMoney rounds a money amount to cents under the given mode.
+func RoundMoney(v float64, mode RoundingMode) float64 {
+	cents := v * 100
+	switch mode {
+	case RoundHalfEven:
+		return math.RoundToEven(cents) / 100
+	case RoundDown:
+		return math.Trunc(cents) / 100
+	default:
+		return math.Round(cents) / 100
+	}
+}
+
+// CalculateTotal calculates the total price of line items, rounding to
+// cents with RoundHalfUp. A zero quantity is treated as 1.
+func CalculateTotal(items []LineItem) float64 {
+	return CalculateTotalRounded(items, RoundHalfUp)
+}
+
+// CalculateTotalRounded is CalculateTotal with an explicit rounding
+// mode.
+func CalculateTotalRounded(items []LineItem, mode RoundingMode) float64 {
+	var total float64
+	for _, item := range items {
+		price := item.Price
+		quantity := item.Quantity
+		if quantity == 0 {
+			quantity = 1
+		}
+		discount := item.Discount
+		itemTotal := price * float64(quantity) * (1 - discount/100)
+		total += itemTotal
+	}
+	return RoundMoney(total, mode)
+}
+
+// ApplyShipping adds the per-country shipping rate to the subtotal.
+// Orders over 100 ship free; unknown countries pay the default rate.
+func ApplyShipping(subtotal float64, country string) float64 {
+	baseRate, ok := shippingRates[country]
+	if !ok {
+		baseRate = 24.99
+	}
+	if subtotal > 100 {
+		return subtotal
+	}
+	return subtotal + baseRate
+}
+
+// orderLineItems converts order items to the shared line-item form.
+func orderLineItems(items []OrderItem) []LineItem {
+	lineItems := make([]LineItem, len(items))
+	for i, item := range items {
+		lineItems[i] = LineItem{
+			Price:    item.Price,
+			Quantity: item.Quantity,
+			Discount: item.Discount,
+			Name:     item.Name,
+		}
+	}
+	return lineItems
+}
+
+// CalculateOrderTotal calculates the total price of items.
+func CalculateOrderTotal(items []OrderItem) float64 {
+	return CalculateTotal(orderLineItems(items))
+}
+
+// CalculateTotalInCurrency totals a mixed-currency order in the target
+// currency. Each item's price is converted via the rates map (units of
+// target per unit of item currency) before the usual quantity and
+// discount math; items without a currency are assumed to already be in
+// the target. A missing rate is an error.
+func CalculateTotalInCurrency(items []OrderItem, rates map[string]float64, target string) (float64, error) {
+	converted := make([]OrderItem, len(items))
+	for i, item := range items {
+		if item.Currency != "" && item.Currency != target {
+			rate, ok := rates[item.Currency]
+			if !ok {
+				return 0, fmt.Errorf("no exchange rate for %s", item.Currency)
+			}
+			item.Price *= rate
+		}
+		converted[i] = item
+	}
+	return CalculateOrderTotal(converted), nil
+}
+
+var shippingRates = map[string]float64{
+	"US": 5.99,
+	"CA": 8.99,
+	"UK": 12.99,
+	"DE": 14.99,
+	"FR": 14.99,
+	"AU": 19.99,
+}
+
+// ApplyShippingCost applies shipping cost based on country.
+func ApplyShippingCost(subtotal float64, country string) float64 {
+	return ApplyShipping(subtotal, country)
+}
+
+var taxRates = map[string]float64{
+	"CA": 0.0725,
+	"NY": 0.08,
+	"TX": 0.0625,
+	"FL": 0.06,
+	"WA": 0.065,
+}
+
+// ApplyItemizedTax returns the total tax for an order, taxing each
+// non-exempt line at the given rate and skipping TaxExempt items such
+// as groceries. Unlike ApplyTax it returns only the tax, not the
+// subtotal plus tax.
+func ApplyItemizedTax(items []OrderItem, rate float64) float64 {
+	var taxable []OrderItem
+	for _, item := range items {
+		if !item.TaxExempt {
+			taxable = append(taxable, item)
+		}
+	}
+	return math.Round(CalculateOrderTotal(taxable)*rate*100) / 100
+}
+
+// ApplyTax applies tax based on state, rounding with RoundHalfUp.
+func ApplyTax(subtotal float64, state string) float64 {
+	return ApplyTaxRounded(subtotal, state, RoundHalfUp)
+}
+
+// ApplyTaxRounded is ApplyTax with an explicit rounding mode.
+func ApplyTaxRounded(subtotal float64, state string, mode RoundingMode) float64 {
+	rate, ok := taxRates[state]
+	if !ok {
+		rate = 0.0
+	}
+	tax := subtotal * rate
+	return RoundMoney(subtotal+tax, mode)
+}
+
+// PromoCode is a registered discount code: either a percentage off or
+// a fixed amount off, optionally expiring at a point in time.
+type PromoCode struct {
+	Percent   float64
+	FixedOff  float64
+	ExpiresAt time.Time
+}
+
+// promoCodes holds the registered promo codes by name.
+var promoCodes = map[string]PromoCode{}
+
+// RegisterPromoCode adds or replaces a named promo code. A zero
+// ExpiresAt means the code never expires.
+func RegisterPromoCode(code string, promo PromoCode) {
+	promoCodes[code] = promo
+}
+
+// ApplyPromoCode applies a registered promo code to the subtotal,
+// erroring on unknown or expired codes. Percentage is applied before
+// the fixed amount; the result is floored at 0 and rounded to cents.
+func ApplyPromoCode(subtotal float64, code string) (float64, error) {
+	promo, ok := promoCodes[code]
+	if !ok {
+		return 0, fmt.Errorf("unknown promo code %q", code)
+	}
+	if !promo.ExpiresAt.IsZero() && time.Now().After(promo.ExpiresAt) {
+		return 0, fmt.Errorf("promo code %q expired", code)
+	}
+	discounted := subtotal*(1-promo.Percent/100) - promo.FixedOff
+	return RoundMoney(math.Max(discounted, 0), RoundHalfUp), nil
+}
+
+// formatSummary renders the shared order/invoice summary layout. The
+// two flows differ only in the title and ID label.
+func formatSummary(title, idLabel, id, customerName, date string, items []LineItem, subtotal, shipping, tax, total float64) string {
+	var lines []string
+	lines = append(lines, strings.Repeat("=", 50))
+	lines = append(lines, title)
+	lines = append(lines, strings.Repeat("=", 50))
+	if id == "" {
+		id = "N/A"
+	}
+	if customerName == "" {
+		customerName = "Unknown"
+	}
+	if date == "" {
+		date = "Unknown"
+	}
+	lines = append(lines, fmt.Sprintf("%s: %s", idLabel, id))
+	lines = append(lines, fmt.Sprintf("Customer: %s", customerName))
+	lines = append(lines, fmt.Sprintf("Date: %s", date))
+	lines = append(lines, strings.Repeat("-", 50))
+	for _, item := range items {
+		lines = append(lines, fmt.Sprintf("  %s: $%.2f", item.Name, item.Price))
+	}
+	lines = append(lines, strings.Repeat("-", 50))
+	lines = append(lines, fmt.Sprintf("Subtotal: $%.2f", subtotal))
+	lines = append(lines, fmt.Sprintf("Shipping: $%.2f", shipping))
+	lines = append(lines, fmt.Sprintf("Tax: $%.2f", tax))
+	lines = append(lines, fmt.Sprintf("Total: $%.2f", total))
+	lines = append(lines, strings.Repeat("=", 50))
+	return strings.Join(lines, "\n")
+}
+
+// FormatOrderSummary formats the order summary for display.
+func FormatOrderSummary(order Order) string {
+	return formatSummary("ORDER SUMMARY", "Order ID", order.ID, order.CustomerName, order.Date,
+		orderLineItems(order.Items), order.Subtotal, order.Shipping, order.Tax, order.Total)
+}
+
+// InvoiceItem represents an item in an invoice.
+type InvoiceItem struct {
+	Price    float64
+	Quantity int
+	Discount float64
+	Name     string
+}
+
+// Invoice represents a complete invoice.
+type Invoice struct {
+	ID           string
+	CustomerName string
+	Date         string
+	Items        []InvoiceItem
+	Subtotal     float64
+	Shipping     float64
+	Tax          float64
+	Total        float64
+}
+
+// invoiceLineItems converts invoice items to the shared line-item form.
+func invoiceLineItems(items []InvoiceItem) []LineItem {
+	lineItems := make([]LineItem, len(items))
+	for i, item := range items {
+		lineItems[i] = LineItem(item)
+	}
+	return lineItems
+}
+
+// CalculateInvoiceTotal calculates the total price of items.
+func CalculateInvoiceTotal(items []InvoiceItem) float64 {
+	return CalculateTotal(invoiceLineItems(items))
+}
+
+// ApplyDeliveryCost applies delivery cost based on country.
+func ApplyDeliveryCost(subtotal float64, country string) float64 {
+	return ApplyShipping(subtotal, country)
+}
+
+// ApplyVat applies VAT based on state.
+func ApplyVat(subtotal float64, state string) float64 {
+	return ApplyTax(subtotal, state)
+}
+
+// FormatInvoiceSummary formats the invoice summary for display.
+func FormatInvoiceSummary(invoice Invoice) string {
+	return formatSummary("INVOICE SUMMARY", "Invoice ID", invoice.ID, invoice.CustomerName, invoice.Date,
+		invoiceLineItems(invoice.Items), invoice.Subtotal, invoice.Shipping, invoice.Tax, invoice.Total)
+}
@@ -0,0 +1,162 @@
+package billing
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCalculateTotal(t *testing.T) {
+	items := []LineItem{
+		{Price: 10, Quantity: 2, Discount: 10}, // 18.00
+		{Price: 5, Quantity: 0},                // zero quantity treated as 1
+	}
+	if got := CalculateTotal(items); got != 23 {
+		t.Fatalf("CalculateTotal = %v, want 23", got)
+	}
+}
+
+func TestRoundMoney(t *testing.T) {
+	// 1.125 is exactly representable, so v*100 is exactly 112.5.
+	if got := RoundMoney(1.125, RoundHalfUp); got != 1.13 {
+		t.Fatalf("half-up = %v", got)
+	}
+	if got := RoundMoney(1.125, RoundHalfEven); got != 1.12 {
+		t.Fatalf("half-even = %v", got)
+	}
+	if got := RoundMoney(1.019, RoundDown); got != 1.01 {
+		t.Fatalf("down = %v", got)
+	}
+}
+
+func TestOrderInvoiceParity(t *testing.T) {
+	orderItems := []OrderItem{{Price: 9.99, Quantity: 3, Discount: 5, Name: "widget"}}
+	invoiceItems := []InvoiceItem{{Price: 9.99, Quantity: 3, Discount: 5, Name: "widget"}}
+	if o, i := CalculateOrderTotal(orderItems), CalculateInvoiceTotal(invoiceItems); o != i {
+		t.Fatalf("order total %v != invoice total %v", o, i)
+	}
+	if s, d := ApplyShipping(50, "US"), ApplyDeliveryCost(50, "US"); s != d {
+		t.Fatalf("shipping %v != delivery %v", s, d)
+	}
+	if tx, v := ApplyTax(100, "NY"), ApplyVat(100, "NY"); tx != v {
+		t.Fatalf("tax %v != vat %v", tx, v)
+	}
+}
+
+func TestApplyShipping(t *testing.T) {
+	if got := ApplyShipping(50, "US"); got != 55.99 {
+		t.Fatalf("US shipping = %v", got)
+	}
+	if got := ApplyShipping(150, "US"); got != 150 {
+		t.Fatalf("free shipping over 100 = %v", got)
+	}
+	if got := ApplyShipping(50, "XX"); got != 74.99 {
+		t.Fatalf("default rate = %v", got)
+	}
+}
+
+func TestCalculateTotalInCurrency(t *testing.T) {
+	items := []OrderItem{
+		{Price: 10, Quantity: 1, Currency: "EUR"},
+		{Price: 5, Quantity: 1}, // already in target
+	}
+	rates := map[string]float64{"EUR": 1.1}
+	got, err := CalculateTotalInCurrency(items, rates, "USD")
+	if err != nil || got != 16 {
+		t.Fatalf("total = %v, %v; want 16", got, err)
+	}
+	if _, err := CalculateTotalInCurrency([]OrderItem{{Price: 1, Currency: "GBP"}}, rates, "USD"); err == nil {
+		t.Fatal("missing rate should error")
+	}
+}
+
+func TestApplyItemizedTax(t *testing.T) {
+	items := []OrderItem{
+		{Price: 100, Quantity: 1},
+		{Price: 50, Quantity: 1, TaxExempt: true},
+	}
+	if got := ApplyItemizedTax(items, 0.1); got != 10 {
+		t.Fatalf("itemized tax = %v, want 10", got)
+	}
+}
+
+func TestPromoCodes(t *testing.T) {
+	RegisterPromoCode("TEN", PromoCode{Percent: 10})
+	got, err := ApplyPromoCode(100, "TEN")
+	if err != nil || got != 90 {
+		t.Fatalf("TEN = %v, %v", got, err)
+	}
+	RegisterPromoCode("BIG", PromoCode{FixedOff: 500})
+	if got, _ := ApplyPromoCode(100, "BIG"); got != 0 {
+		t.Fatalf("floored discount = %v, want 0", got)
+	}
+	if _, err := ApplyPromoCode(100, "NOPE"); err == nil {
+		t.Fatal("unknown code should error")
+	}
+	RegisterPromoCode("OLD", PromoCode{Percent: 10, ExpiresAt: time.Now().Add(-time.Hour)})
+	if _, err := ApplyPromoCode(100, "OLD"); err == nil {
+		t.Fatal("expired code should error")
+	}
+}
+
+func TestFormatSummaries(t *testing.T) {
+	order := Order{ID: "o1", CustomerName: "Ada", Date: "2024-01-01",
+		Items: []OrderItem{{Name: "widget", Price: 9.99}}, Subtotal: 9.99, Total: 9.99}
+	out := FormatOrderSummary(order)
+	for _, want := range []string{"ORDER SUMMARY", "Order ID: o1", "Customer: Ada", "widget: $9.99", "Total: $9.99"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("order summary missing %q:\n%s", want, out)
+		}
+	}
+	inv := Invoice{Items: []InvoiceItem{{Name: "widget", Price: 9.99}}}
+	invOut := FormatInvoiceSummary(inv)
+	for _, want := range []string{"INVOICE SUMMARY", "Invoice ID: N/A", "Customer: Unknown"} {
+		if !strings.Contains(invOut, want) {
+			t.Fatalf("invoice summary missing %q:\n%s", want, invOut)
+		}
+	}
+}
+
+func TestTierDiscounts(t *testing.T) {
+	tier := DiscountTier{Base: 10, Max: 25, Threshold: 100}
+	if got := CalculateTierDiscount(50, tier); got != 45 {
+		t.Fatalf("below threshold = %v, want 45", got)
+	}
+	// Far above the threshold the discount caps at Max.
+	if got := CalculateTierDiscount(10000, tier); math.Abs(got-7500) > 1e-9 {
+		t.Fatalf("capped discount = %v, want 7500", got)
+	}
+	// The legacy per-tier wrappers agree with the parameterized form.
+	for price := 10.0; price <= 400; price += 37 {
+		if CalculateBronzeTierDiscount(price) != CalculateTierDiscount(price, DiscountTier{Base: 5, Max: 15, Threshold: 100}) {
+			t.Fatalf("bronze wrapper diverged at %v", price)
+		}
+	}
+}
+
+func TestCalculateDiscountByName(t *testing.T) {
+	got, err := CalculateDiscountByName(50, "gold")
+	if err != nil || got != CalculateGoldTierDiscount(50) {
+		t.Fatalf("gold by name = %v, %v", got, err)
+	}
+	if _, err := CalculateDiscountByName(50, "platinum"); err == nil {
+		t.Fatal("unknown tier should error")
+	}
+	RegisterTier("platinum", DiscountTier{Base: 20, Max: 40, Threshold: 300})
+	if _, err := CalculateDiscountByName(50, "platinum"); err != nil {
+		t.Fatalf("registered tier should resolve: %v", err)
+	}
+}
+
+func TestApplyDiscounts(t *testing.T) {
+	twenty := DiscountTier{Base: 20, Max: 20, Threshold: 1e9}
+	ten := DiscountTier{Base: 10, Max: 10, Threshold: 1e9}
+	got := ApplyDiscounts(100, twenty, ten)
+	if math.Abs(got-72) > 1e-9 {
+		t.Fatalf("stacked = %v, want 72 (multiplicative)", got)
+	}
+	if got := ApplyDiscounts(100); got != 100 {
+		t.Fatalf("no discounts = %v", got)
+	}
+}
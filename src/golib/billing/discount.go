@@ -0,0 +1,79 @@
+// Package billing provides order, invoice, discount, and promo-code
+// calculations with explicit money rounding.
+package billing
+
+import (
+	"fmt"
+	"math"
+)
+
+// DiscountTier describes a discount schedule: Base percent below the
+// price Threshold, growing toward Max percent above it.
+type DiscountTier struct {
+	Base      float64
+	Max       float64
+	Threshold float64
+}
+
+// CalculateTierDiscount calculates the discounted price for a tier.
+// Below the threshold the base discount applies; above it the discount
+// grows by 2% of the excess, capped at the tier maximum.
+func CalculateTierDiscount(price float64, tier DiscountTier) float64 {
+	if price < tier.Threshold {
+		return price * (1 - tier.Base/100)
+	}
+
+	additional := (price - tier.Threshold) * 0.02
+	totalDiscount := math.Min(tier.Base+additional, tier.Max)
+	return price * (1 - totalDiscount/100)
+}
+
+// discountTiers holds the named tiers available to
+// CalculateDiscountByName. New tiers can be added at runtime via
+// RegisterTier without a code change.
+var discountTiers = map[string]DiscountTier{
+	"bronze": {Base: 5, Max: 15, Threshold: 100},
+	"silver": {Base: 10, Max: 25, Threshold: 150},
+	"gold":   {Base: 15, Max: 35, Threshold: 200},
+}
+
+// RegisterTier adds or replaces a named discount tier.
+func RegisterTier(name string, tier DiscountTier) {
+	discountTiers[name] = tier
+}
+
+// CalculateDiscountByName calculates the discounted price for a named
+// tier, erroring on unknown tier names.
+func CalculateDiscountByName(price float64, tierName string) (float64, error) {
+	tier, ok := discountTiers[tierName]
+	if !ok {
+		return 0, fmt.Errorf("unknown discount tier %q", tierName)
+	}
+	return CalculateTierDiscount(price, tier), nil
+}
+
+// ApplyDiscounts applies each discount tier multiplicatively in order,
+// so stacking 20% and 10% yields price*0.8*0.9 rather than an additive
+// 30% off. The result is floored at 0 so stacked discounts can never
+// produce a negative price.
+func ApplyDiscounts(price float64, discounts ...DiscountTier) float64 {
+	for _, tier := range discounts {
+		price = CalculateTierDiscount(price, tier)
+	}
+	return math.Max(price, 0)
+}
+
+// CalculateBronzeTierDiscount calculates discount for bronze tier customers.
+func CalculateBronzeTierDiscount(price float64) float64 {
+	return CalculateTierDiscount(price, DiscountTier{Base: 5, Max: 15, Threshold: 100})
+}
+
+// CalculateSilverTierDiscount calculates discount for silver tier customers.
+func CalculateSilverTierDiscount(price float64) float64 {
+	return CalculateTierDiscount(price, DiscountTier{Base: 10, Max: 25, Threshold: 150})
+}
+
+// CalculateGoldTierDiscount calculates discount for gold tier customers.
+func CalculateGoldTierDiscount(price float64) float64 {
+	return CalculateTierDiscount(price, DiscountTier{Base: 15, Max: 35, Threshold: 200})
+}
@@ -0,0 +1,751 @@
+package concurrent
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestInMemoryRepositoryCRUD(t *testing.T) {
+	r := NewInMemoryRepository()
+	if err := r.Save("alpha"); err != nil {
+		t.Fatal(err)
+	}
+	v, err := r.Find(1)
+	if err != nil || v != "alpha" {
+		t.Fatalf("Find = %v, %v", v, err)
+	}
+	if err := r.Update(1, "beta"); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Update(99, "x"); err == nil {
+		t.Fatal("Update of missing id should fail")
+	}
+	if !r.Exists(1) || r.Exists(99) {
+		t.Fatal("Exists gave wrong answer")
+	}
+	if err := r.Delete(1); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Delete(1); err == nil {
+		t.Fatal("second Delete should fail")
+	}
+}
+
+func TestRepositoryHooks(t *testing.T) {
+	r := NewInMemoryRepository()
+	var savedID, deletedID int
+	r.OnSave(func(id int, _ interface{}) { savedID = id })
+	r.OnDelete(func(id int) { deletedID = id })
+	r.Save("x")
+	if savedID != 1 {
+		t.Fatalf("save hook saw id %d", savedID)
+	}
+	r.Delete(1)
+	if deletedID != 1 {
+		t.Fatalf("delete hook saw id %d", deletedID)
+	}
+}
+
+func TestRepositoryFindPageQueryCount(t *testing.T) {
+	r := NewInMemoryRepository()
+	for i := 0; i < 5; i++ {
+		r.Save(i)
+	}
+	page, err := r.FindPage(1, 2)
+	if err != nil || !reflect.DeepEqual(page, []interface{}{1, 2}) {
+		t.Fatalf("FindPage = %v, %v", page, err)
+	}
+	if _, err := r.FindPage(0, 0); err == nil {
+		t.Fatal("limit 0 should error")
+	}
+	empty, err := r.FindPage(10, 2)
+	if err != nil || len(empty) != 0 {
+		t.Fatalf("offset past end = %v, %v", empty, err)
+	}
+	even := func(v interface{}) bool { return v.(int)%2 == 0 }
+	if got := r.Count(even); got != 3 {
+		t.Fatalf("Count = %d, want 3", got)
+	}
+	if got := len(r.Query(even)); got != 3 {
+		t.Fatalf("Query len = %d, want 3", got)
+	}
+}
+
+func TestRepositoryFileRoundTrip(t *testing.T) {
+	r := NewInMemoryRepository()
+	r.Save("hello")
+	path := filepath.Join(t.TempDir(), "repo.json")
+	if err := r.SaveToFile(path); err != nil {
+		t.Fatal(err)
+	}
+	back := NewInMemoryRepository()
+	if err := back.LoadFromFile(path); err != nil {
+		t.Fatal(err)
+	}
+	v, err := back.Find(1)
+	if err != nil || v != "hello" {
+		t.Fatalf("loaded Find = %v, %v", v, err)
+	}
+}
+
+func TestGenericRepository(t *testing.T) {
+	r := NewGenericRepository[string]()
+	r.Save(7, "seven")
+	v, ok := r.Find(7)
+	if !ok || v != "seven" {
+		t.Fatalf("Find = %q, %v", v, ok)
+	}
+	if !r.Delete(7) || r.Delete(7) {
+		t.Fatal("Delete bookkeeping wrong")
+	}
+}
+
+func TestWorkerPoolHandlerAndStats(t *testing.T) {
+	pool := NewWorkerPool(3, func(job Job) Result {
+		return Result{JobID: job.ID, Success: job.ID%2 == 0}
+	})
+	for i := 0; i < 6; i++ {
+		pool.Submit(Job{ID: i})
+	}
+	pool.Wait()
+	stats := pool.Stats()
+	if stats.Submitted != 6 || stats.Completed != 3 || stats.Failed != 3 || stats.InFlight != 0 {
+		t.Fatalf("stats = %+v", stats)
+	}
+	pool.Shutdown()
+}
+
+func TestWorkerPoolPanicRecovery(t *testing.T) {
+	pool := NewWorkerPool(1, func(job Job) Result {
+		panic("handler exploded")
+	})
+	pool.Submit(Job{ID: 1})
+	pool.Wait()
+	r := <-pool.Results()
+	if r.Success || r.Error == nil {
+		t.Fatalf("panicking handler should yield a failed result, got %+v", r)
+	}
+	pool.Shutdown()
+}
+
+func TestWorkerPoolRetry(t *testing.T) {
+	var attempts atomic.Int64
+	pool := NewWorkerPool(1, func(job Job) Result {
+		n := attempts.Add(1)
+		return Result{JobID: job.ID, Success: n >= 3}
+	})
+	pool.SubmitWithRetry(Job{ID: 1}, 5, time.Millisecond)
+	pool.Wait()
+	r := <-pool.Results()
+	if !r.Success {
+		t.Fatalf("job should succeed on third attempt, got %+v", r)
+	}
+	if got := attempts.Load(); got != 3 {
+		t.Fatalf("attempts = %d, want 3", got)
+	}
+	pool.Shutdown()
+}
+
+func TestTypedPool(t *testing.T) {
+	pool := NewPool[int, int](2, func(in int) (int, error) {
+		return in * in, nil
+	})
+	for i := 1; i <= 3; i++ {
+		pool.Submit(i)
+	}
+	pool.Close()
+	sum := 0
+	for out := range pool.Results() {
+		sum += out
+	}
+	if sum != 14 {
+		t.Fatalf("sum of squares = %d, want 14", sum)
+	}
+}
+
+func TestPipelineNamedStageError(t *testing.T) {
+	p := NewPipeline()
+	p.AddNamedStage("double", func(_ context.Context, v interface{}) (interface{}, error) {
+		return v.(int) * 2, nil
+	})
+	p.AddNamedStage("boom", func(_ context.Context, v interface{}) (interface{}, error) {
+		return nil, errors.New("nope")
+	})
+	_, err := p.Execute(context.Background(), 1)
+	if err == nil || !strings.Contains(err.Error(), `stage "boom" failed`) {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestPipelineConditionalAndCompensation(t *testing.T) {
+	var undone []string
+	p := NewPipeline()
+	p.AddCompensatingStage(
+		func(_ context.Context, v interface{}) (interface{}, error) { return v, nil },
+		func(_ context.Context, v interface{}) (interface{}, error) {
+			undone = append(undone, "first")
+			return v, nil
+		},
+	)
+	p.AddConditionalStage(
+		func(v interface{}) bool { return v.(int) > 10 },
+		func(_ context.Context, v interface{}) (interface{}, error) { return v.(int) * 100, nil },
+	)
+	p.AddStage(func(_ context.Context, v interface{}) (interface{}, error) {
+		return nil, errors.New("fail late")
+	})
+
+	if _, err := p.Execute(context.Background(), 1); err == nil {
+		t.Fatal("expected failure")
+	}
+	if !reflect.DeepEqual(undone, []string{"first"}) {
+		t.Fatalf("undo actions = %v", undone)
+	}
+
+	// Conditional stage passes small values through untouched.
+	p2 := NewPipeline()
+	p2.AddConditionalStage(
+		func(v interface{}) bool { return v.(int) > 10 },
+		func(_ context.Context, v interface{}) (interface{}, error) { return v.(int) * 100, nil },
+	)
+	out, err := p2.Execute(context.Background(), 5)
+	if err != nil || out != 5 {
+		t.Fatalf("conditional passthrough = %v, %v", out, err)
+	}
+}
+
+func TestTypedPipeline(t *testing.T) {
+	p := NewTypedPipeline[int]()
+	p.AddStage(func(_ context.Context, v int) (int, error) { return v + 1, nil })
+	p.AddStage(func(_ context.Context, v int) (int, error) { return v * 3, nil })
+	out, err := p.Execute(context.Background(), 1)
+	if err != nil || out != 6 {
+		t.Fatalf("Execute = %d, %v", out, err)
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	rl := NewRateLimiter(2, time.Hour)
+	defer rl.Stop()
+	if !rl.TryAcquire() || !rl.TryAcquire() {
+		t.Fatal("first two acquires should succeed")
+	}
+	if rl.TryAcquire() {
+		t.Fatal("third acquire should fail before refill")
+	}
+	if got := rl.Available(); got != 0 {
+		t.Fatalf("Available = %d, want 0", got)
+	}
+}
+
+func TestSemaphore(t *testing.T) {
+	s := NewSemaphore(1)
+	if !s.TryAcquire() {
+		t.Fatal("first TryAcquire should succeed")
+	}
+	if s.TryAcquire() {
+		t.Fatal("second TryAcquire should fail")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := s.Acquire(ctx); err == nil {
+		t.Fatal("Acquire on full semaphore should time out")
+	}
+	s.Release()
+	defer func() {
+		if recover() == nil {
+			t.Fatal("over-release should panic")
+		}
+	}()
+	s.Release()
+}
+
+func TestFuture(t *testing.T) {
+	f := Async(func() (int, error) { return 42, nil })
+	v, err := f.Get(context.Background())
+	if err != nil || v != 42 {
+		t.Fatalf("Get = %d, %v", v, err)
+	}
+	// Cached: a second Get sees the same result.
+	if v, _ := f.Get(context.Background()); v != 42 {
+		t.Fatalf("second Get = %d", v)
+	}
+
+	blocked := Async(func() (int, error) {
+		time.Sleep(time.Hour)
+		return 0, nil
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := blocked.Get(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("cancelled Get err = %v", err)
+	}
+}
+
+func TestGroupDoCoalesces(t *testing.T) {
+	var g Group
+	var calls atomic.Int64
+	release := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := g.Do("key", func() (interface{}, error) {
+				calls.Add(1)
+				<-release
+				return "shared", nil
+			})
+			if err != nil || v != "shared" {
+				t.Errorf("Do = %v, %v", v, err)
+			}
+		}()
+	}
+	// Let the goroutines pile up on the key, then release the leader.
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("fn ran %d times, want 1", got)
+	}
+}
+
+func TestGroupDoPanicReleasesWaiters(t *testing.T) {
+	var g Group
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	panics := make(chan interface{}, 3)
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { panics <- recover() }()
+			<-start
+			g.Do("k", func() (interface{}, error) {
+				time.Sleep(50 * time.Millisecond)
+				panic("boom")
+			})
+		}()
+	}
+	close(start)
+	done := make(chan struct{})
+	go func() { wg.Wait(); close(done) }()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("waiters blocked after panic")
+	}
+	for i := 0; i < 3; i++ {
+		if p := <-panics; p != "boom" {
+			t.Fatalf("caller %d recovered %v, want boom", i, p)
+		}
+	}
+	// The key must be forgotten so a later Do runs fn again.
+	v, err := g.Do("k", func() (interface{}, error) { return 42, nil })
+	if err != nil || v != 42 {
+		t.Fatalf("Do after panic = %v, %v", v, err)
+	}
+}
+
+func TestParallelMap(t *testing.T) {
+	in := []int{1, 2, 3, 4, 5}
+	var inFlight, peak atomic.Int64
+	out, err := ParallelMap(context.Background(), in, 2, func(_ context.Context, v int) (int, error) {
+		cur := inFlight.Add(1)
+		for {
+			p := peak.Load()
+			if cur <= p || peak.CompareAndSwap(p, cur) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		inFlight.Add(-1)
+		return v * v, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(out, []int{1, 4, 9, 16, 25}) {
+		t.Fatalf("out = %v", out)
+	}
+	if peak.Load() > 2 {
+		t.Fatalf("peak concurrency = %d, want <= 2", peak.Load())
+	}
+
+	sentinel := errors.New("bad element")
+	_, err = ParallelMap(context.Background(), in, 2, func(ctx context.Context, v int) (int, error) {
+		if v == 3 {
+			return 0, sentinel
+		}
+		return v, nil
+	})
+	if !errors.Is(err, sentinel) {
+		t.Fatalf("err = %v, want sentinel", err)
+	}
+
+	if _, err := ParallelMap(context.Background(), in, 0, func(_ context.Context, v int) (int, error) {
+		return v, nil
+	}); err == nil {
+		t.Fatal("concurrency 0 should error")
+	}
+}
+
+func TestCircuitBreakerTripAndRecover(t *testing.T) {
+	cb := NewCircuitBreaker(2, 30*time.Millisecond)
+	fail := func() error { return errors.New("down") }
+	cb.Execute(fail)
+	cb.Execute(fail)
+	if cb.State() != StateOpen {
+		t.Fatalf("state = %v, want open", cb.State())
+	}
+	if err := cb.Execute(func() error { return nil }); err == nil {
+		t.Fatal("open breaker should reject calls")
+	}
+	time.Sleep(40 * time.Millisecond)
+	for i := 0; i < 2; i++ {
+		if err := cb.Execute(func() error { return nil }); err != nil {
+			t.Fatalf("half-open probe %d: %v", i, err)
+		}
+	}
+	if cb.State() != StateClosed {
+		t.Fatalf("state after probes = %v, want closed", cb.State())
+	}
+}
+
+func TestCircuitBreakerDoAndTimeout(t *testing.T) {
+	cb := NewCircuitBreaker(3, time.Minute)
+	v, err := Do(cb, func() (string, error) { return "ok", nil })
+	if err != nil || v != "ok" {
+		t.Fatalf("Do = %q, %v", v, err)
+	}
+	err = cb.ExecuteTimeout(10*time.Millisecond, func() error {
+		time.Sleep(time.Second)
+		return nil
+	})
+	if err == nil {
+		t.Fatal("slow call should count as timeout failure")
+	}
+}
+
+func TestLeakyBucketSpacing(t *testing.T) {
+	const interval = 20 * time.Millisecond
+	lb := NewLeakyBucket(interval)
+	defer lb.Stop()
+
+	ctx := context.Background()
+	start := time.Now()
+	last := start
+	var gaps []time.Duration
+	for i := 0; i < 3; i++ {
+		if err := lb.Acquire(ctx); err != nil {
+			t.Fatal(err)
+		}
+		now := time.Now()
+		gaps = append(gaps, now.Sub(last))
+		last = now
+	}
+	// Three drips cannot arrive faster than three intervals, and each
+	// consecutive pair must be roughly an interval apart. The lower
+	// bounds are slightly loose to tolerate scheduler jitter.
+	if elapsed := time.Since(start); elapsed < 3*interval-interval/2 {
+		t.Fatalf("3 acquires took %v, want >= ~%v", elapsed, 3*interval)
+	}
+	for i, gap := range gaps {
+		if gap < interval/2 {
+			t.Fatalf("gap %d = %v, want >= ~%v (no banking)", i, gap, interval)
+		}
+	}
+}
+
+func TestLeakyBucketAcquireCancellation(t *testing.T) {
+	lb := NewLeakyBucket(time.Hour)
+	defer lb.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := lb.Acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want deadline exceeded", err)
+	}
+}
+
+func TestCircuitBreakerWindowRatio(t *testing.T) {
+	cb := NewCircuitBreakerWindow(10, 0.5, time.Minute)
+	boom := errors.New("boom")
+
+	// Six failures and three successes: only nine outcomes recorded,
+	// so the window is incomplete and the breaker must stay closed.
+	for i := 0; i < 6; i++ {
+		cb.Execute(func() error { return boom })
+	}
+	for i := 0; i < 3; i++ {
+		cb.Execute(func() error { return nil })
+	}
+	if got := cb.State(); got != StateClosed {
+		t.Fatalf("state before window fills = %v, want closed", got)
+	}
+
+	// The tenth call completes the window at 6/10 failures, which
+	// exceeds the 0.5 ratio and opens the breaker even though the
+	// call itself succeeds.
+	if err := cb.Execute(func() error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	if got := cb.State(); got != StateOpen {
+		t.Fatalf("state after 6/10 failures = %v, want open", got)
+	}
+	if err := cb.Execute(func() error { return nil }); err == nil || !strings.Contains(err.Error(), "circuit breaker is open") {
+		t.Fatalf("open breaker returned %v", err)
+	}
+}
+
+func TestCircuitBreakerFailurePredicate(t *testing.T) {
+	cb := NewCircuitBreaker(2, time.Minute)
+	notFound := errors.New("not found")
+	refused := errors.New("connection refused")
+	cb.SetFailurePredicate(func(err error) bool { return errors.Is(err, refused) })
+
+	// Rejected errors are surfaced to the caller but never counted,
+	// so driving them past the threshold leaves the breaker closed.
+	for i := 0; i < 5; i++ {
+		if err := cb.Execute(func() error { return notFound }); !errors.Is(err, notFound) {
+			t.Fatalf("err = %v, want notFound", err)
+		}
+	}
+	if got := cb.State(); got != StateClosed {
+		t.Fatalf("state after uncounted errors = %v, want closed", got)
+	}
+
+	// Errors the predicate accepts still trip the breaker.
+	cb.Execute(func() error { return refused })
+	cb.Execute(func() error { return refused })
+	if got := cb.State(); got != StateOpen {
+		t.Fatalf("state after counted errors = %v, want open", got)
+	}
+}
+
+func TestCircuitBreakerHalfOpenProbeLimit(t *testing.T) {
+	cb := NewCircuitBreaker(1, 20*time.Millisecond)
+	cb.SetMaxHalfOpenProbes(1)
+	cb.Execute(func() error { return errors.New("boom") })
+	if got := cb.State(); got != StateOpen {
+		t.Fatalf("state = %v, want open", got)
+	}
+	time.Sleep(30 * time.Millisecond)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- cb.Execute(func() error {
+			close(started)
+			<-release
+			return nil
+		})
+	}()
+	<-started
+
+	// The single probe slot is taken by the blocked call, so a second
+	// caller is rejected as if the breaker were still open.
+	err := cb.Execute(func() error { return nil })
+	if err == nil || !strings.Contains(err.Error(), "circuit breaker is open") {
+		t.Fatalf("second half-open call returned %v", err)
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if got := cb.State(); got != StateClosed {
+		t.Fatalf("state after successful probe = %v, want closed", got)
+	}
+}
+
+func TestRateLimiterAcquireN(t *testing.T) {
+	rl := NewRateLimiter(5, time.Hour)
+	defer rl.Stop()
+
+	if err := rl.AcquireN(context.Background(), 3); err != nil {
+		t.Fatal(err)
+	}
+	if got := rl.Available(); got != 2 {
+		t.Fatalf("Available after AcquireN(3) = %d, want 2", got)
+	}
+	if err := rl.AcquireN(context.Background(), 6); err == nil {
+		t.Fatal("AcquireN beyond bucket capacity should fail")
+	}
+}
+
+func TestRateLimiterAcquireNRefundsOnCancel(t *testing.T) {
+	rl := NewRateLimiter(5, time.Hour)
+	defer rl.Stop()
+	for i := 0; i < 4; i++ {
+		if !rl.TryAcquire() {
+			t.Fatal("drain failed")
+		}
+	}
+
+	// Only one token remains: AcquireN(3) takes it, blocks on the
+	// rest, and must give it back when the context expires.
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	if err := rl.AcquireN(ctx, 3); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want deadline exceeded", err)
+	}
+	if got := rl.Available(); got != 1 {
+		t.Fatalf("Available after refund = %d, want 1", got)
+	}
+}
+
+func TestRateLimiterSetRateChangesThroughput(t *testing.T) {
+	rl := NewRateLimiter(1, time.Hour)
+	defer rl.Stop()
+	if !rl.TryAcquire() {
+		t.Fatal("initial token missing")
+	}
+
+	// With an hour-long refill the next Acquire would block all test;
+	// after SetRate the faster cadence must let it through quickly.
+	rl.SetRate(1, 10*time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := rl.Acquire(ctx); err != nil {
+		t.Fatalf("Acquire after SetRate = %v", err)
+	}
+}
+
+func TestRateLimiterAcquireSurvivesSetRate(t *testing.T) {
+	rl := NewRateLimiter(1, time.Hour)
+	defer rl.Stop()
+	rl.TryAcquire()
+
+	done := make(chan error, 1)
+	go func() { done <- rl.Acquire(context.Background()) }()
+	time.Sleep(20 * time.Millisecond)
+
+	// The blocked acquirer must move to the reconfigured bucket and
+	// complete once the new refill cadence produces a token.
+	rl.SetRate(2, 10*time.Millisecond)
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Acquire did not survive reconfiguration")
+	}
+}
+
+func TestRateLimiterStopIdempotent(t *testing.T) {
+	rl := NewRateLimiter(1, time.Hour)
+	rl.Stop()
+	rl.Stop() // must not panic on a closed channel
+
+	// SetRate on a stopped limiter swaps the bucket without reviving
+	// the refill goroutine, and a subsequent Stop stays a no-op.
+	rl.SetRate(3, time.Millisecond)
+	rl.Stop()
+	if got := rl.Available(); got > 3 {
+		t.Fatalf("Available = %d, want <= 3", got)
+	}
+}
+
+func TestPipelineStageTimeout(t *testing.T) {
+	p := NewPipeline()
+	p.AddStageWithTimeout(20*time.Millisecond, func(ctx context.Context, v interface{}) (interface{}, error) {
+		select {
+		case <-time.After(time.Second):
+			return v, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	})
+	_, err := p.Execute(context.Background(), 1)
+	if err == nil || !strings.Contains(err.Error(), "stage timed out after 20ms") {
+		t.Fatalf("err = %v, want stage timeout", err)
+	}
+
+	// A stage that finishes inside its budget passes through.
+	p2 := NewPipeline()
+	p2.AddStageWithTimeout(time.Second, func(_ context.Context, v interface{}) (interface{}, error) {
+		return v.(int) + 1, nil
+	})
+	out, err := p2.Execute(context.Background(), 1)
+	if err != nil || out != 2 {
+		t.Fatalf("fast stage = %v, %v", out, err)
+	}
+
+	// A cancelled outer context is reported as the caller's
+	// cancellation, not dressed up as a stage timeout.
+	p3 := NewPipeline()
+	p3.AddStageWithTimeout(time.Second, func(ctx context.Context, v interface{}) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := p3.Execute(ctx, 1); err == nil || strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("outer cancellation misreported: %v", err)
+	}
+}
+
+func TestPipelineCompensationOrder(t *testing.T) {
+	var undone []string
+	undo := func(name string) func(context.Context, interface{}) (interface{}, error) {
+		return func(_ context.Context, v interface{}) (interface{}, error) {
+			undone = append(undone, name)
+			return v, nil
+		}
+	}
+	pass := func(_ context.Context, v interface{}) (interface{}, error) { return v, nil }
+
+	p := NewPipeline()
+	p.AddCompensatingStage(pass, undo("first"))
+	p.AddCompensatingStage(pass, undo("second"))
+	p.AddStage(func(_ context.Context, v interface{}) (interface{}, error) {
+		return nil, errors.New("fail late")
+	})
+
+	if _, err := p.Execute(context.Background(), 1); err == nil {
+		t.Fatal("expected failure")
+	}
+	// Compensations run in reverse registration order.
+	if !reflect.DeepEqual(undone, []string{"second", "first"}) {
+		t.Fatalf("undo order = %v, want [second first]", undone)
+	}
+}
+
+func TestWorkerPoolShutdownContext(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	pool := NewWorkerPool(1, func(job Job) Result {
+		close(started)
+		<-release
+		return Result{JobID: job.ID, Success: true}
+	})
+	pool.Submit(Job{ID: 1})
+	<-started
+
+	// The in-flight job is blocked, so a short deadline must expire
+	// before the queue drains.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := pool.ShutdownContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want deadline exceeded", err)
+	}
+
+	// Workers and channels survive the failed shutdown: releasing the
+	// handler lets the job complete and its result arrive normally.
+	close(release)
+	pool.Wait()
+	if r := <-pool.Results(); !r.Success {
+		t.Fatalf("result after failed shutdown = %+v", r)
+	}
+	pool.Shutdown()
+}
@@ -0,0 +1,3 @@
+module github.com/alexander-stage-hoco/project-caldera/src/golib
+
+go 1.21
@@ -0,0 +1,257 @@
+// Package report renders record reports in text, grouped, Markdown,
+// HTML, and JSON forms with shared field defaulting.
+package report
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"sort"
+	"strings"
+)
+
+// ReportItem represents an item in a report.
+type ReportItem struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Email     string `json:"email"`
+	Status    string `json:"status"`
+	CreatedAt string `json:"createdAt"`
+}
+
+// applyReportDefaults fills in the placeholder values used for blank
+// fields in report output.
+func applyReportDefaults(item ReportItem) ReportItem {
+	if item.ID == "" {
+		item.ID = "N/A"
+	}
+	if item.Name == "" {
+		item.Name = "Unknown"
+	}
+	if item.Email == "" {
+		item.Email = "N/A"
+	}
+	if item.Status == "" {
+		item.Status = "active"
+	}
+	if item.CreatedAt == "" {
+		item.CreatedAt = "Unknown"
+	}
+	return item
+}
+
+// reportLines builds the lines of a formatted report. The per-record
+// formatting and field defaulting is shared by all report variants.
+func reportLines(title string, items []ReportItem) []string {
+	var lines []string
+	lines = append(lines, strings.Repeat("=", 60))
+	lines = append(lines, title)
+	lines = append(lines, strings.Repeat("=", 60))
+	lines = append(lines, "")
+
+	for _, item := range items {
+		item = applyReportDefaults(item)
+		lines = append(lines, fmt.Sprintf("ID: %s", item.ID))
+		lines = append(lines, fmt.Sprintf("Name: %s", item.Name))
+		lines = append(lines, fmt.Sprintf("Email: %s", item.Email))
+		lines = append(lines, fmt.Sprintf("Status: %s", item.Status))
+		lines = append(lines, fmt.Sprintf("Created: %s", item.CreatedAt))
+		lines = append(lines, strings.Repeat("-", 40))
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, fmt.Sprintf("Total records: %d", len(items)))
+	lines = append(lines, strings.Repeat("=", 60))
+	return lines
+}
+
+// GenerateReport creates a formatted report with the given title.
+func GenerateReport(title string, items []ReportItem) string {
+	return strings.Join(reportLines(title, items), "\n")
+}
+
+// WriteReport streams a formatted report to w line by line through a
+// buffered writer, avoiding building the whole report in memory. Each
+// line is terminated with a newline; write errors are propagated.
+func WriteReport(w io.Writer, title string, items []ReportItem) error {
+	bw := bufio.NewWriter(w)
+	for _, line := range reportLines(title, items) {
+		if _, err := bw.WriteString(line); err != nil {
+			return err
+		}
+		if err := bw.WriteByte('\n'); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// GenerateReportSorted creates a formatted report with records sorted
+// by the given key before rendering. Valid keys are "id", "name",
+// "email", "status", and "created"; anything else is an error. The sort
+// is stable, so records with equal keys keep their input order.
+func GenerateReportSorted(title string, items []ReportItem, sortBy string, desc bool) (string, error) {
+	var key func(ReportItem) string
+	switch sortBy {
+	case "id":
+		key = func(item ReportItem) string { return item.ID }
+	case "name":
+		key = func(item ReportItem) string { return item.Name }
+	case "email":
+		key = func(item ReportItem) string { return item.Email }
+	case "status":
+		key = func(item ReportItem) string { return item.Status }
+	case "created":
+		key = func(item ReportItem) string { return item.CreatedAt }
+	default:
+		return "", fmt.Errorf("unknown sort key %q", sortBy)
+	}
+
+	sorted := make([]ReportItem, len(items))
+	copy(sorted, items)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if desc {
+			return key(sorted[i]) > key(sorted[j])
+		}
+		return key(sorted[i]) < key(sorted[j])
+	})
+	return GenerateReport(title, sorted), nil
+}
+
+// GenerateReportWithColumns creates a formatted report emitting only
+// the requested columns per record, in the given order. Valid column
+// names are "id", "name", "email", "status", and "created"; unknown
+// names are skipped silently.
+func GenerateReportWithColumns(title string, items []ReportItem, columns []string) string {
+	var lines []string
+	lines = append(lines, strings.Repeat("=", 60))
+	lines = append(lines, title)
+	lines = append(lines, strings.Repeat("=", 60))
+	lines = append(lines, "")
+
+	for _, item := range items {
+		item = applyReportDefaults(item)
+		for _, col := range columns {
+			switch col {
+			case "id":
+				lines = append(lines, fmt.Sprintf("ID: %s", item.ID))
+			case "name":
+				lines = append(lines, fmt.Sprintf("Name: %s", item.Name))
+			case "email":
+				lines = append(lines, fmt.Sprintf("Email: %s", item.Email))
+			case "status":
+				lines = append(lines, fmt.Sprintf("Status: %s", item.Status))
+			case "created":
+				lines = append(lines, fmt.Sprintf("Created: %s", item.CreatedAt))
+			}
+		}
+		lines = append(lines, strings.Repeat("-", 40))
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, fmt.Sprintf("Total records: %d", len(items)))
+	lines = append(lines, strings.Repeat("=", 60))
+	return strings.Join(lines, "\n")
+}
+
+// GenerateGroupedReport creates a formatted report with records
+// grouped by their (defaulted) Status. Each group is preceded by a
+// status header and followed by a "Subtotal: N" line; groups appear in
+// sorted status order for deterministic output.
+func GenerateGroupedReport(title string, items []ReportItem) string {
+	groups := make(map[string][]ReportItem)
+	for _, item := range items {
+		item = applyReportDefaults(item)
+		groups[item.Status] = append(groups[item.Status], item)
+	}
+	statuses := make([]string, 0, len(groups))
+	for status := range groups {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+
+	var lines []string
+	lines = append(lines, strings.Repeat("=", 60))
+	lines = append(lines, title)
+	lines = append(lines, strings.Repeat("=", 60))
+	lines = append(lines, "")
+
+	for _, status := range statuses {
+		lines = append(lines, fmt.Sprintf("STATUS: %s", status))
+		for _, item := range groups[status] {
+			lines = append(lines, fmt.Sprintf("ID: %s", item.ID))
+			lines = append(lines, fmt.Sprintf("Name: %s", item.Name))
+			lines = append(lines, fmt.Sprintf("Email: %s", item.Email))
+			lines = append(lines, fmt.Sprintf("Created: %s", item.CreatedAt))
+			lines = append(lines, strings.Repeat("-", 40))
+		}
+		lines = append(lines, fmt.Sprintf("Subtotal: %d", len(groups[status])))
+		lines = append(lines, "")
+	}
+
+	lines = append(lines, fmt.Sprintf("Total records: %d", len(items)))
+	lines = append(lines, strings.Repeat("=", 60))
+	return strings.Join(lines, "\n")
+}
+
+// GenerateReportMarkdown returns the items as a GitHub-flavored
+// Markdown table with the blank-field defaults applied.
+func GenerateReportMarkdown(items []ReportItem) string {
+	var b strings.Builder
+	b.WriteString("| ID | Name | Email | Status | Created |\n")
+	b.WriteString("| --- | --- | --- | --- | --- |\n")
+	for _, item := range items {
+		item = applyReportDefaults(item)
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n",
+			item.ID, item.Name, item.Email, item.Status, item.CreatedAt)
+	}
+	return b.String()
+}
+
+// GenerateReportHTML returns the items as an HTML table. All field
+// values are escaped so markup in Name or Email cannot inject into the
+// page embedding the report.
+func GenerateReportHTML(items []ReportItem) string {
+	var b strings.Builder
+	b.WriteString("<table>\n")
+	b.WriteString("<tr><th>ID</th><th>Name</th><th>Email</th><th>Status</th><th>Created</th></tr>\n")
+	for _, item := range items {
+		item = applyReportDefaults(item)
+		fmt.Fprintf(&b, "<tr><td>%s</td><td>%s</td><td>%s</td><td>%s</td><td>%s</td></tr>\n",
+			html.EscapeString(item.ID),
+			html.EscapeString(item.Name),
+			html.EscapeString(item.Email),
+			html.EscapeString(item.Status),
+			html.EscapeString(item.CreatedAt))
+	}
+	b.WriteString("</table>\n")
+	return b.String()
+}
+
+// GenerateReportJSON returns the items as a JSON array with the same
+// blank-field defaults applied as the text reports. Empty input
+// marshals to [] rather than null.
+func GenerateReportJSON(items []ReportItem) ([]byte, error) {
+	defaulted := make([]ReportItem, len(items))
+	for i, item := range items {
+		defaulted[i] = applyReportDefaults(item)
+	}
+	return json.Marshal(defaulted)
+}
+
+// GenerateUserReport creates a formatted report for users.
+func GenerateUserReport(users []ReportItem) string {
+	return GenerateReport("USER REPORT", users)
+}
+
+// GenerateAdminReport creates a formatted report for admins.
+func GenerateAdminReport(admins []ReportItem) string {
+	return GenerateReport("ADMIN REPORT", admins)
+}
+
+// GenerateGuestReport creates a formatted report for guests.
+func GenerateGuestReport(guests []ReportItem) string {
+	return GenerateReport("GUEST REPORT", guests)
+}
@@ -0,0 +1,138 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+var sample = []ReportItem{
+	{ID: "2", Name: "Bob", Email: "bob@x", Status: "inactive", CreatedAt: "2024-01-02"},
+	{ID: "1", Name: "Alice", Email: "alice@x", Status: "active", CreatedAt: "2024-01-01"},
+}
+
+func TestGenerateReportDefaults(t *testing.T) {
+	out := GenerateReport("TEST", []ReportItem{{}})
+	for _, want := range []string{"ID: N/A", "Name: Unknown", "Email: N/A", "Status: active", "Created: Unknown", "Total records: 1"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("report missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRoleWrappersShareLayout(t *testing.T) {
+	if got := GenerateUserReport(sample); got != GenerateReport("USER REPORT", sample) {
+		t.Fatal("GenerateUserReport should delegate to GenerateReport")
+	}
+	if !strings.Contains(GenerateAdminReport(sample), "ADMIN REPORT") {
+		t.Fatal("admin title missing")
+	}
+	if !strings.Contains(GenerateGuestReport(sample), "GUEST REPORT") {
+		t.Fatal("guest title missing")
+	}
+}
+
+func TestWriteReportMatchesGenerate(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteReport(&buf, "TEST", sample); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), GenerateReport("TEST", sample)+"\n"; got != want {
+		t.Fatalf("WriteReport output differs from GenerateReport")
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestWriteReportPropagatesErrors(t *testing.T) {
+	if err := WriteReport(failingWriter{}, "TEST", sample); err == nil {
+		t.Fatal("write errors should propagate")
+	}
+}
+
+func TestGenerateReportSorted(t *testing.T) {
+	out, err := GenerateReportSorted("TEST", sample, "name", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Index(out, "Alice") > strings.Index(out, "Bob") {
+		t.Fatal("ascending name sort order wrong")
+	}
+	desc, err := GenerateReportSorted("TEST", sample, "id", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Index(desc, "ID: 2") > strings.Index(desc, "ID: 1") {
+		t.Fatal("descending id sort order wrong")
+	}
+	if _, err := GenerateReportSorted("TEST", sample, "height", false); err == nil {
+		t.Fatal("unknown sort key should error")
+	}
+}
+
+func TestGenerateReportWithColumns(t *testing.T) {
+	out := GenerateReportWithColumns("TEST", sample, []string{"name", "id"})
+	if strings.Contains(out, "Email:") {
+		t.Fatal("unselected column rendered")
+	}
+	if strings.Index(out, "Name: Bob") > strings.Index(out, "ID: 2") {
+		t.Fatal("columns should render in requested order")
+	}
+}
+
+func TestGenerateGroupedReport(t *testing.T) {
+	out := GenerateGroupedReport("TEST", sample)
+	for _, want := range []string{"STATUS: active", "STATUS: inactive", "Subtotal: 1", "Total records: 2"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("grouped report missing %q:\n%s", want, out)
+		}
+	}
+	// Groups are emitted in sorted status order.
+	if strings.Index(out, "STATUS: active") > strings.Index(out, "STATUS: inactive") {
+		t.Fatal("group order not sorted")
+	}
+}
+
+func TestGenerateReportMarkdown(t *testing.T) {
+	out := GenerateReportMarkdown(sample)
+	if !strings.HasPrefix(out, "| ID | Name | Email | Status | Created |\n| --- |") {
+		t.Fatalf("markdown header wrong:\n%s", out)
+	}
+	if !strings.Contains(out, "| 1 | Alice | alice@x | active | 2024-01-01 |") {
+		t.Fatalf("markdown row missing:\n%s", out)
+	}
+}
+
+func TestGenerateReportHTMLEscapes(t *testing.T) {
+	out := GenerateReportHTML([]ReportItem{{Name: "<script>alert(1)</script>"}})
+	if strings.Contains(out, "<script>") {
+		t.Fatal("HTML output must escape markup in fields")
+	}
+	if !strings.Contains(out, "&lt;script&gt;") {
+		t.Fatalf("escaped name missing:\n%s", out)
+	}
+}
+
+func TestGenerateReportJSON(t *testing.T) {
+	data, err := GenerateReportJSON([]ReportItem{{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var back []ReportItem
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatal(err)
+	}
+	if back[0].Name != "Unknown" || back[0].Status != "active" {
+		t.Fatalf("defaults not applied: %+v", back[0])
+	}
+	empty, err := GenerateReportJSON(nil)
+	if err != nil || string(empty) != "[]" {
+		t.Fatalf("empty input = %s, %v; want []", empty, err)
+	}
+}
@@ -0,0 +1,174 @@
+// Package service provides a concurrency-safe generic item service.
+package service
+
+import (
+	"sync"
+)
+
+// NestedItem represents an item in the nested service.
+type NestedItem struct {
+	ID        int
+	Name      string
+	CreatedAt int64
+}
+
+// Service provides item management for any record type, keyed by an id
+// accessor so Find, Update, and Delete know which field identifies an
+// item.
+type Service[T any] struct {
+	items []T
+	id    func(T) int
+	mu    sync.RWMutex
+}
+
+// NewService creates a new service using id to extract item
+// identifiers.
+func NewService[T any](id func(T) int) *Service[T] {
+	return &Service[T]{
+		items: make([]T, 0),
+		id:    id,
+	}
+}
+
+// Add adds an item.
+func (s *Service[T]) Add(item T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.items = append(s.items, item)
+}
+
+// Find searches for an item by ID.
+func (s *Service[T]) Find(id int) *T {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for i := range s.items {
+		if s.id(s.items[i]) == id {
+			return &s.items[i]
+		}
+	}
+	return nil
+}
+
+// Update replaces the item with the given ID, reporting whether it was
+// found.
+func (s *Service[T]) Update(id int, item T) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.items {
+		if s.id(s.items[i]) == id {
+			s.items[i] = item
+			return true
+		}
+	}
+	return false
+}
+
+// Delete removes the item with the given ID, preserving the order of
+// the remaining items. It reports whether an item was removed.
+func (s *Service[T]) Delete(id int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.items {
+		if s.id(s.items[i]) == id {
+			s.items = append(s.items[:i], s.items[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// FindBy returns copies of all items matching the predicate, in input
+// order.
+func (s *Service[T]) FindBy(pred func(T) bool) []T {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []T
+	for _, item := range s.items {
+		if pred(item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// FindFirst returns a pointer to the first item matching the
+// predicate, or false when nothing matches.
+func (s *Service[T]) FindFirst(pred func(T) bool) (*T, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for i := range s.items {
+		if pred(s.items[i]) {
+			return &s.items[i], true
+		}
+	}
+	return nil, false
+}
+
+// GetAll returns all items.
+func (s *Service[T]) GetAll() []T {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]T, len(s.items))
+	copy(result, s.items)
+	return result
+}
+
+// Each calls fn for each item under the read lock, stopping early when
+// fn returns false. Unlike GetAll it does not copy the items. Because
+// the read lock is held for the whole iteration, mutating the service
+// from inside fn will deadlock.
+func (s *Service[T]) Each(fn func(T) bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, item := range s.items {
+		if !fn(item) {
+			return
+		}
+	}
+}
+
+// Clear removes all items.
+func (s *Service[T]) Clear() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count := len(s.items)
+	s.items = s.items[:0]
+	return count
+}
+
+// NestedService provides item management. It is the NestedItem
+// instantiation of Service, kept for compatibility.
+type NestedService struct {
+	Service[NestedItem]
+}
+
+// NewNestedService creates a new service.
+func NewNestedService() *NestedService {
+	return &NestedService{
+		Service: Service[NestedItem]{
+			items: make([]NestedItem, 0),
+			id:    func(item NestedItem) int { return item.ID },
+		},
+	}
+}
+
+// NestedProcessor processes items.
+type NestedProcessor interface {
+	Process(item NestedItem) (bool, error)
+}
+
+// AsyncProcessor implements NestedProcessor.
+type AsyncProcessor struct{}
+
+// Process processes an item asynchronously.
+func (p *AsyncProcessor) Process(item NestedItem) (bool, error) {
+	return item.ID > 0, nil
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestServiceCRUD(t *testing.T) {
+	s := NewService(func(v NestedItem) int { return v.ID })
+	s.Add(NestedItem{ID: 1, Name: "a"})
+	s.Add(NestedItem{ID: 2, Name: "b"})
+
+	if got := s.Find(2); got == nil || got.Name != "b" {
+		t.Fatalf("Find(2) = %v", got)
+	}
+	if s.Find(9) != nil {
+		t.Fatal("Find of missing id should be nil")
+	}
+	if !s.Update(1, NestedItem{ID: 1, Name: "a2"}) {
+		t.Fatal("Update of existing id should succeed")
+	}
+	if s.Update(9, NestedItem{ID: 9}) {
+		t.Fatal("Update of missing id should fail")
+	}
+	if !s.Delete(1) || s.Delete(1) {
+		t.Fatal("Delete bookkeeping wrong")
+	}
+	if got := len(s.GetAll()); got != 1 {
+		t.Fatalf("GetAll len = %d, want 1", got)
+	}
+	if got := s.Clear(); got != 1 {
+		t.Fatalf("Clear = %d, want 1", got)
+	}
+}
+
+func TestServiceDeletePreservesOrder(t *testing.T) {
+	s := NewService(func(v int) int { return v })
+	for _, v := range []int{1, 2, 3, 4} {
+		s.Add(v)
+	}
+	s.Delete(2)
+	if got := s.GetAll(); !reflect.DeepEqual(got, []int{1, 3, 4}) {
+		t.Fatalf("after Delete = %v", got)
+	}
+}
+
+func TestServiceFindByFindFirstEach(t *testing.T) {
+	s := NewService(func(v NestedItem) int { return v.ID })
+	for i := 1; i <= 5; i++ {
+		s.Add(NestedItem{ID: i})
+	}
+	even := s.FindBy(func(v NestedItem) bool { return v.ID%2 == 0 })
+	if len(even) != 2 {
+		t.Fatalf("FindBy = %v", even)
+	}
+	first, ok := s.FindFirst(func(v NestedItem) bool { return v.ID > 3 })
+	if !ok || first.ID != 4 {
+		t.Fatalf("FindFirst = %v, %v", first, ok)
+	}
+	if _, ok := s.FindFirst(func(v NestedItem) bool { return v.ID > 99 }); ok {
+		t.Fatal("FindFirst with no match should report !ok")
+	}
+	var seen []int
+	s.Each(func(v NestedItem) bool {
+		seen = append(seen, v.ID)
+		return v.ID < 3 // stop after visiting 3
+	})
+	if !reflect.DeepEqual(seen, []int{1, 2, 3}) {
+		t.Fatalf("Each visited %v", seen)
+	}
+}
+
+func TestNestedServiceCompat(t *testing.T) {
+	s := NewNestedService()
+	s.Add(NestedItem{ID: 7, Name: "seven"})
+	if got := s.Find(7); got == nil || got.Name != "seven" {
+		t.Fatalf("Find(7) = %v", got)
+	}
+}
+
+func TestServiceConcurrent(t *testing.T) {
+	s := NewService(func(v int) int { return v })
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(v int) {
+			defer wg.Done()
+			s.Add(v)
+			s.Find(v)
+		}(i)
+	}
+	wg.Wait()
+	if got := len(s.GetAll()); got != 100 {
+		t.Fatalf("len = %d, want 100", got)
+	}
+}
+
+func TestAsyncProcessor(t *testing.T) {
+	p := &AsyncProcessor{}
+	ok, err := p.Process(NestedItem{ID: 1})
+	if err != nil || !ok {
+		t.Fatalf("Process = %v, %v", ok, err)
+	}
+	if ok, _ := p.Process(NestedItem{ID: 0}); ok {
+		t.Fatal("non-positive ID should not process")
+	}
+}
@@ -0,0 +1,169 @@
+// Package simple provides small user, counter, and generic slice
+// helpers.
+package simple
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// User represents a system user.
+type User struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	Email  string `json:"email"`
+	Active bool   `json:"active"`
+}
+
+// MarshalJSON serializes the user, rejecting invalid users so
+// malformed records never reach the API. The value receiver ensures
+// the check fires whether a User or a *User is marshaled.
+func (u User) MarshalJSON() ([]byte, error) {
+	if !u.IsValid() {
+		return nil, fmt.Errorf("cannot marshal invalid user %d", u.ID)
+	}
+	type alias User
+	return json.Marshal(alias(u))
+}
+
+// Greet returns a greeting message for the user.
+func (u *User) Greet() string {
+	return fmt.Sprintf("Hello, %s!", u.Name)
+}
+
+// IsValid checks if the user has valid data.
+func (u *User) IsValid() bool {
+	return u.ID > 0 && u.Name != "" && strings.Contains(u.Email, "@")
+}
+
+// Counter is a simple counter implementation, safe for concurrent use.
+// It is backed by an atomic integer, so Increment and friends are
+// lock-free.
+type Counter struct {
+	value atomic.Int64
+}
+
+// NewCounter creates a new counter with initial value.
+func NewCounter(initial int) *Counter {
+	c := &Counter{}
+	c.value.Store(int64(initial))
+	return c
+}
+
+// Increment increases the counter by 1.
+func (c *Counter) Increment() {
+	c.Add(1)
+}
+
+// Decrement decreases the counter by 1.
+func (c *Counter) Decrement() {
+	c.Add(-1)
+}
+
+// Add changes the counter by n in one step.
+func (c *Counter) Add(n int) {
+	c.value.Add(int64(n))
+}
+
+// Reset sets the counter back to zero.
+func (c *Counter) Reset() {
+	c.value.Store(0)
+}
+
+// CompareAndSwap sets the counter to new only if it currently holds
+// old, reporting whether the swap happened.
+func (c *Counter) CompareAndSwap(old, new int) bool {
+	return c.value.CompareAndSwap(int64(old), int64(new))
+}
+
+// Value returns the current counter value.
+func (c *Counter) Value() int {
+	return int(c.value.Load())
+}
+
+// Add sums two integers.
+func Add(a, b int) int {
+	return a + b
+}
+
+// Multiply multiplies two integers.
+func Multiply(a, b int) int {
+	return a * b
+}
+
+// Divide divides a by b, returns error if b is zero.
+func Divide(a, b int) (int, error) {
+	if b == 0 {
+		return 0, fmt.Errorf("division by zero")
+	}
+	return a / b, nil
+}
+
+// Filter returns the elements of s for which pred is true, preserving
+// order.
+func Filter[T any](s []T, pred func(T) bool) []T {
+	var out []T
+	for _, v := range s {
+		if pred(v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// Map applies fn to each element of s and returns the results.
+func Map[T, R any](s []T, fn func(T) R) []R {
+	out := make([]R, len(s))
+	for i, v := range s {
+		out[i] = fn(v)
+	}
+	return out
+}
+
+// SortUsers stably sorts users in place by the named field ("id",
+// "name", or "email"), descending when desc is set. Unknown fields are
+// an error.
+func SortUsers(users []User, by string, desc bool) error {
+	var less func(a, b User) bool
+	switch by {
+	case "id":
+		less = func(a, b User) bool { return a.ID < b.ID }
+	case "name":
+		less = func(a, b User) bool { return a.Name < b.Name }
+	case "email":
+		less = func(a, b User) bool { return a.Email < b.Email }
+	default:
+		return fmt.Errorf("unknown sort field %q", by)
+	}
+	sort.SliceStable(users, func(i, j int) bool {
+		if desc {
+			return less(users[j], users[i])
+		}
+		return less(users[i], users[j])
+	})
+	return nil
+}
+
+// GroupBy buckets the elements of s by the key function. Elements
+// within each bucket keep their input order.
+func GroupBy[T any, K comparable](s []T, key func(T) K) map[K][]T {
+	groups := make(map[K][]T)
+	for _, v := range s {
+		k := key(v)
+		groups[k] = append(groups[k], v)
+	}
+	return groups
+}
+
+// FilterActiveUsers returns only active users from the slice.
+func FilterActiveUsers(users []User) []User {
+	return Filter(users, func(u User) bool { return u.Active })
+}
+
+// MapUserNames extracts names from a slice of users.
+func MapUserNames(users []User) []string {
+	return Map(users, func(u User) string { return u.Name })
+}
@@ -0,0 +1,125 @@
+package simple
+
+import (
+	"encoding/json"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestUserMarshalJSON(t *testing.T) {
+	good := User{ID: 1, Name: "Ada", Email: "ada@example.com", Active: true}
+	b, err := json.Marshal(good)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"id":1,"name":"Ada","email":"ada@example.com","active":true}`
+	if string(b) != want {
+		t.Fatalf("marshal = %s, want %s", b, want)
+	}
+
+	bad := User{ID: 0, Name: "", Email: "nope"}
+	if _, err := json.Marshal(bad); err == nil {
+		t.Fatal("marshaling an invalid user value should fail")
+	}
+	if _, err := json.Marshal(&bad); err == nil {
+		t.Fatal("marshaling an invalid user pointer should fail")
+	}
+}
+
+func TestCounter(t *testing.T) {
+	c := NewCounter(10)
+	c.Increment()
+	c.Decrement()
+	c.Add(5)
+	if got := c.Value(); got != 15 {
+		t.Fatalf("Value = %d, want 15", got)
+	}
+	if !c.CompareAndSwap(15, 20) {
+		t.Fatal("CAS from current value should succeed")
+	}
+	if c.CompareAndSwap(15, 99) {
+		t.Fatal("CAS from stale value should fail")
+	}
+	c.Reset()
+	if got := c.Value(); got != 0 {
+		t.Fatalf("after Reset Value = %d", got)
+	}
+}
+
+func TestCounterConcurrent(t *testing.T) {
+	c := NewCounter(0)
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				c.Increment()
+			}
+		}()
+	}
+	wg.Wait()
+	if got := c.Value(); got != 10000 {
+		t.Fatalf("Value = %d, want 10000", got)
+	}
+}
+
+func TestFilterMapGroupBy(t *testing.T) {
+	nums := []int{1, 2, 3, 4, 5}
+	evens := Filter(nums, func(v int) bool { return v%2 == 0 })
+	if !reflect.DeepEqual(evens, []int{2, 4}) {
+		t.Fatalf("Filter = %v", evens)
+	}
+	doubled := Map(nums, func(v int) int { return v * 2 })
+	if !reflect.DeepEqual(doubled, []int{2, 4, 6, 8, 10}) {
+		t.Fatalf("Map = %v", doubled)
+	}
+	groups := GroupBy(nums, func(v int) string {
+		if v%2 == 0 {
+			return "even"
+		}
+		return "odd"
+	})
+	if !reflect.DeepEqual(groups["odd"], []int{1, 3, 5}) {
+		t.Fatalf("GroupBy odd = %v", groups["odd"])
+	}
+}
+
+func TestSortUsers(t *testing.T) {
+	users := []User{
+		{ID: 2, Name: "b"},
+		{ID: 1, Name: "c"},
+		{ID: 3, Name: "a"},
+	}
+	if err := SortUsers(users, "name", false); err != nil {
+		t.Fatal(err)
+	}
+	if users[0].Name != "a" || users[2].Name != "c" {
+		t.Fatalf("sorted by name = %v", users)
+	}
+	if err := SortUsers(users, "id", true); err != nil {
+		t.Fatal(err)
+	}
+	if users[0].ID != 3 {
+		t.Fatalf("sorted by id desc = %v", users)
+	}
+	if err := SortUsers(users, "height", false); err == nil {
+		t.Fatal("unknown field should error")
+	}
+}
+
+func TestUserHelpers(t *testing.T) {
+	users := []User{
+		{ID: 1, Name: "a", Email: "a@x", Active: true},
+		{ID: 2, Name: "b", Email: "b@x", Active: false},
+	}
+	active := FilterActiveUsers(users)
+	if len(active) != 1 || active[0].ID != 1 {
+		t.Fatalf("FilterActiveUsers = %v", active)
+	}
+	names := MapUserNames(users)
+	if !reflect.DeepEqual(names, []string{"a", "b"}) {
+		t.Fatalf("MapUserNames = %v", names)
+	}
+}
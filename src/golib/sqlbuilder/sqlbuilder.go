@@ -0,0 +1,49 @@
+// Package sqlbuilder assembles parameterized SELECT statements so
+// callers never interpolate values into SQL text.
+package sqlbuilder
+
+import "strings"
+
+// QueryBuilder collects columns, a table, and placeholder conditions
+// for a SELECT. Values passed to Where are gathered into an args slice
+// for the driver; only column and table names chosen by the programmer
+// appear in the query string.
+type QueryBuilder struct {
+	columns []string
+	table   string
+	conds   []string
+	args    []interface{}
+}
+
+// Select starts a query over the given columns.
+func Select(cols ...string) *QueryBuilder {
+	return &QueryBuilder{columns: cols}
+}
+
+// From sets the table to query.
+func (qb *QueryBuilder) From(table string) *QueryBuilder {
+	qb.table = table
+	return qb
+}
+
+// Where adds a condition with placeholder parameters, e.g.
+// Where("username = ?", name). Multiple conditions are ANDed.
+func (qb *QueryBuilder) Where(cond string, args ...interface{}) *QueryBuilder {
+	qb.conds = append(qb.conds, cond)
+	qb.args = append(qb.args, args...)
+	return qb
+}
+
+// Build returns the parameterized query string and its args slice,
+// ready for db.Query(query, args...).
+func (qb *QueryBuilder) Build() (string, []interface{}) {
+	cols := "*"
+	if len(qb.columns) > 0 {
+		cols = strings.Join(qb.columns, ", ")
+	}
+	query := "SELECT " + cols + " FROM " + qb.table
+	if len(qb.conds) > 0 {
+		query += " WHERE " + strings.Join(qb.conds, " AND ")
+	}
+	return query, qb.args
+}
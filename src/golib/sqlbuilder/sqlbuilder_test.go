@@ -0,0 +1,42 @@
+package sqlbuilder
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuildSelectAll(t *testing.T) {
+	query, args := Select().From("users").Build()
+	if query != "SELECT * FROM users" {
+		t.Fatalf("query = %q", query)
+	}
+	if len(args) != 0 {
+		t.Fatalf("args = %v", args)
+	}
+}
+
+func TestBuildWithColumnsAndConditions(t *testing.T) {
+	query, args := Select("id", "email").
+		From("users").
+		Where("username = ?", "ada").
+		Where("active = ?", true).
+		Build()
+	want := "SELECT id, email FROM users WHERE username = ? AND active = ?"
+	if query != want {
+		t.Fatalf("query = %q, want %q", query, want)
+	}
+	if !reflect.DeepEqual(args, []interface{}{"ada", true}) {
+		t.Fatalf("args = %v", args)
+	}
+}
+
+func TestValuesNeverEnterQueryString(t *testing.T) {
+	malicious := "'; DROP TABLE users; --"
+	query, args := Select("*").From("users").Where("name = ?", malicious).Build()
+	if query != "SELECT * FROM users WHERE name = ?" {
+		t.Fatalf("query = %q", query)
+	}
+	if args[0] != malicious {
+		t.Fatalf("args = %v", args)
+	}
+}
@@ -0,0 +1,307 @@
+// Package stats provides batch and streaming descriptive statistics
+// over float64 series.
+package stats
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// DataProcessor processes numerical data.
+type DataProcessor struct {
+	data      []float64
+	processed bool
+}
+
+// NewDataProcessor creates a new DataProcessor.
+func NewDataProcessor(data []float64) *DataProcessor {
+	return &DataProcessor{data: data}
+}
+
+// Normalize normalizes the data to 0-1 range.
+func (dp *DataProcessor) Normalize() []float64 {
+	result, _ := dp.NormalizeRange(0, 1)
+	return result
+}
+
+// NormalizeRange maps the data linearly so min becomes lo and max
+// becomes hi, erroring when lo >= hi. All-equal data maps to the
+// midpoint (lo+hi)/2, preserving the Normalize rule for max == min.
+func (dp *DataProcessor) NormalizeRange(lo, hi float64) ([]float64, error) {
+	if lo >= hi {
+		return nil, errors.New("lo must be less than hi")
+	}
+	if len(dp.data) == 0 {
+		return []float64{}, nil
+	}
+	min, max := dp.data[0], dp.data[0]
+	for _, v := range dp.data {
+		if v < min {
+			min = v
+		}
+		if v > max {
+			max = v
+		}
+	}
+	result := make([]float64, len(dp.data))
+	if max == min {
+		for i := range result {
+			result[i] = (lo + hi) / 2
+		}
+		return result, nil
+	}
+	for i, v := range dp.data {
+		result[i] = lo + (v-min)/(max-min)*(hi-lo)
+	}
+	return result, nil
+}
+
+// Percentile returns the p-th percentile (0-100) of the data using
+// linear interpolation between closest ranks, so Percentile(50)
+// equals the Median from ComputeStatistics. Empty data or p outside
+// [0, 100] is an error.
+func (dp *DataProcessor) Percentile(p float64) (float64, error) {
+	if len(dp.data) == 0 {
+		return 0, errors.New("no data")
+	}
+	if p < 0 || p > 100 {
+		return 0, errors.New("percentile must be in [0, 100]")
+	}
+
+	sorted := make([]float64, len(dp.data))
+	copy(sorted, dp.data)
+	sort.Float64s(sorted)
+
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	if lower == upper {
+		return sorted[lower], nil
+	}
+	frac := rank - float64(lower)
+	return sorted[lower]*(1-frac) + sorted[upper]*frac, nil
+}
+
+// Standardize returns the data transformed to mean 0 and standard
+// deviation 1. When the standard deviation is 0 (all values equal),
+// all zeros are returned, matching how Normalize handles max == min.
+func (dp *DataProcessor) Standardize() []float64 {
+	if len(dp.data) == 0 {
+		return []float64{}
+	}
+
+	stats := dp.ComputeStatistics()
+	result := make([]float64, len(dp.data))
+	if stats.Std == 0 {
+		return result
+	}
+	for i, v := range dp.data {
+		result[i] = (v - stats.Mean) / stats.Std
+	}
+	return result
+}
+
+// MovingAverage returns the simple moving average of the data over the
+// given window. The result has len(data)-window+1 entries, one per full
+// window; window <= 0 or window > len(data) is an error.
+func (dp *DataProcessor) MovingAverage(window int) ([]float64, error) {
+	if window <= 0 {
+		return nil, errors.New("window must be positive")
+	}
+	if window > len(dp.data) {
+		return nil, errors.New("window exceeds data length")
+	}
+
+	result := make([]float64, 0, len(dp.data)-window+1)
+	var sum float64
+	for i, v := range dp.data {
+		sum += v
+		if i >= window {
+			sum -= dp.data[i-window]
+		}
+		if i >= window-1 {
+			result = append(result, sum/float64(window))
+		}
+	}
+	return result, nil
+}
+
+// ExponentialMovingAverage returns the exponentially weighted moving
+// average with smoothing factor alpha in (0, 1], seeded with the first
+// value. The result has the same length as the data.
+func (dp *DataProcessor) ExponentialMovingAverage(alpha float64) ([]float64, error) {
+	if alpha <= 0 || alpha > 1 {
+		return nil, errors.New("alpha must be in (0, 1]")
+	}
+	if len(dp.data) == 0 {
+		return []float64{}, nil
+	}
+
+	result := make([]float64, len(dp.data))
+	result[0] = dp.data[0]
+	for i := 1; i < len(dp.data); i++ {
+		result[i] = alpha*dp.data[i] + (1-alpha)*result[i-1]
+	}
+	return result, nil
+}
+
+// Mode returns all values tied for the highest frequency, sorted
+// ascending. Multimodal data yields multiple values; empty data
+// yields an empty slice.
+func (dp *DataProcessor) Mode() []float64 {
+	if len(dp.data) == 0 {
+		return []float64{}
+	}
+
+	counts := make(map[float64]int)
+	best := 0
+	for _, v := range dp.data {
+		counts[v]++
+		if counts[v] > best {
+			best = counts[v]
+		}
+	}
+
+	var modes []float64
+	for v, c := range counts {
+		if c == best {
+			modes = append(modes, v)
+		}
+	}
+	sort.Float64s(modes)
+	return modes
+}
+
+// RunningStats accumulates statistics incrementally using Welford's
+// algorithm for numerically stable online variance, for streams too
+// large to hold in memory. Variance is the population variance,
+// matching ComputeStatistics.
+type RunningStats struct {
+	count int
+	mean  float64
+	m2    float64
+	min   float64
+	max   float64
+}
+
+// Add incorporates a new value into the running statistics.
+func (rs *RunningStats) Add(x float64) {
+	rs.count++
+	if rs.count == 1 {
+		rs.min, rs.max = x, x
+	} else {
+		if x < rs.min {
+			rs.min = x
+		}
+		if x > rs.max {
+			rs.max = x
+		}
+	}
+	delta := x - rs.mean
+	rs.mean += delta / float64(rs.count)
+	rs.m2 += delta * (x - rs.mean)
+}
+
+// Count returns the number of values added.
+func (rs *RunningStats) Count() int {
+	return rs.count
+}
+
+// Mean returns the mean of the values added so far.
+func (rs *RunningStats) Mean() float64 {
+	return rs.mean
+}
+
+// Variance returns the population variance of the values added so far.
+func (rs *RunningStats) Variance() float64 {
+	if rs.count == 0 {
+		return 0
+	}
+	return rs.m2 / float64(rs.count)
+}
+
+// Std returns the population standard deviation.
+func (rs *RunningStats) Std() float64 {
+	return math.Sqrt(rs.Variance())
+}
+
+// Min returns the smallest value added, or 0 when empty.
+func (rs *RunningStats) Min() float64 {
+	return rs.min
+}
+
+// Max returns the largest value added, or 0 when empty.
+func (rs *RunningStats) Max() float64 {
+	return rs.max
+}
+
+// Correlation returns the Pearson correlation coefficient between two
+// series, in [-1, 1]. The slices must have equal length of at least 2,
+// and neither may have zero variance.
+func Correlation(a, b []float64) (float64, error) {
+	if len(a) != len(b) {
+		return 0, errors.New("series must have equal length")
+	}
+	if len(a) < 2 {
+		return 0, errors.New("need at least 2 values")
+	}
+
+	n := float64(len(a))
+	var sumA, sumB float64
+	for i := range a {
+		sumA += a[i]
+		sumB += b[i]
+	}
+	meanA, meanB := sumA/n, sumB/n
+
+	var cov, varA, varB float64
+	for i := range a {
+		da, db := a[i]-meanA, b[i]-meanB
+		cov += da * db
+		varA += da * da
+		varB += db * db
+	}
+	if varA == 0 || varB == 0 {
+		return 0, errors.New("zero variance")
+	}
+	return cov / math.Sqrt(varA*varB), nil
+}
+
+// Statistics contains computed statistics.
+type Statistics struct {
+	Mean   float64
+	Median float64
+	Std    float64
+}
+
+// ComputeStatistics computes basic statistics on the data.
+func (dp *DataProcessor) ComputeStatistics() Statistics {
+	if len(dp.data) == 0 {
+		return Statistics{}
+	}
+	n := float64(len(dp.data))
+	var sum float64
+	for _, v := range dp.data {
+		sum += v
+	}
+	mean := sum / n
+
+	sorted := make([]float64, len(dp.data))
+	copy(sorted, dp.data)
+	sort.Float64s(sorted)
+	var median float64
+	if len(sorted)%2 != 0 {
+		median = sorted[len(sorted)/2]
+	} else {
+		median = (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
+	}
+
+	var varianceSum float64
+	for _, v := range dp.data {
+		varianceSum += (v - mean) * (v - mean)
+	}
+	std := math.Sqrt(varianceSum / n)
+
+	return Statistics{Mean: mean, Median: median, Std: std}
+}
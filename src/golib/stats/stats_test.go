@@ -0,0 +1,140 @@
+package stats
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestNormalizeAndRange(t *testing.T) {
+	dp := NewDataProcessor([]float64{0, 5, 10})
+	if got := dp.Normalize(); !reflect.DeepEqual(got, []float64{0, 0.5, 1}) {
+		t.Fatalf("Normalize = %v", got)
+	}
+	ranged, err := dp.NormalizeRange(-1, 1)
+	if err != nil || !reflect.DeepEqual(ranged, []float64{-1, 0, 1}) {
+		t.Fatalf("NormalizeRange = %v, %v", ranged, err)
+	}
+	if _, err := dp.NormalizeRange(1, 1); err == nil {
+		t.Fatal("lo >= hi should error")
+	}
+	// All-equal data maps to the midpoint.
+	flat := NewDataProcessor([]float64{3, 3})
+	mid, err := flat.NormalizeRange(0, 10)
+	if err != nil || !reflect.DeepEqual(mid, []float64{5, 5}) {
+		t.Fatalf("flat NormalizeRange = %v, %v", mid, err)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	dp := NewDataProcessor([]float64{1, 2, 3, 4})
+	p50, err := dp.Percentile(50)
+	if err != nil || !almostEqual(p50, dp.ComputeStatistics().Median) {
+		t.Fatalf("Percentile(50) = %v, %v; median = %v", p50, err, dp.ComputeStatistics().Median)
+	}
+	p0, _ := dp.Percentile(0)
+	p100, _ := dp.Percentile(100)
+	if p0 != 1 || p100 != 4 {
+		t.Fatalf("p0 = %v, p100 = %v", p0, p100)
+	}
+	if _, err := dp.Percentile(101); err == nil {
+		t.Fatal("out-of-range percentile should error")
+	}
+	if _, err := NewDataProcessor(nil).Percentile(50); err == nil {
+		t.Fatal("empty data should error")
+	}
+}
+
+func TestStandardize(t *testing.T) {
+	dp := NewDataProcessor([]float64{2, 4, 6})
+	z := dp.Standardize()
+	rs := &RunningStats{}
+	for _, v := range z {
+		rs.Add(v)
+	}
+	if !almostEqual(rs.Mean(), 0) || !almostEqual(rs.Std(), 1) {
+		t.Fatalf("standardized mean = %v, std = %v", rs.Mean(), rs.Std())
+	}
+	if got := NewDataProcessor([]float64{5, 5}).Standardize(); !reflect.DeepEqual(got, []float64{0, 0}) {
+		t.Fatalf("zero-variance Standardize = %v", got)
+	}
+}
+
+func TestMode(t *testing.T) {
+	if got := NewDataProcessor([]float64{1, 2, 2, 3, 3}).Mode(); !reflect.DeepEqual(got, []float64{2, 3}) {
+		t.Fatalf("Mode = %v", got)
+	}
+	if got := NewDataProcessor(nil).Mode(); len(got) != 0 {
+		t.Fatalf("empty Mode = %v", got)
+	}
+}
+
+func TestMovingAverage(t *testing.T) {
+	dp := NewDataProcessor([]float64{1, 2, 3, 4})
+	ma, err := dp.MovingAverage(2)
+	if err != nil || !reflect.DeepEqual(ma, []float64{1.5, 2.5, 3.5}) {
+		t.Fatalf("MovingAverage = %v, %v", ma, err)
+	}
+	if _, err := dp.MovingAverage(0); err == nil {
+		t.Fatal("window 0 should error")
+	}
+	if _, err := dp.MovingAverage(5); err == nil {
+		t.Fatal("window > len should error")
+	}
+}
+
+func TestExponentialMovingAverage(t *testing.T) {
+	dp := NewDataProcessor([]float64{10, 20})
+	ema, err := dp.ExponentialMovingAverage(0.5)
+	if err != nil || !reflect.DeepEqual(ema, []float64{10, 15}) {
+		t.Fatalf("EMA = %v, %v", ema, err)
+	}
+	if _, err := dp.ExponentialMovingAverage(0); err == nil {
+		t.Fatal("alpha 0 should error")
+	}
+	if _, err := dp.ExponentialMovingAverage(1.5); err == nil {
+		t.Fatal("alpha > 1 should error")
+	}
+}
+
+func TestRunningStatsMatchesBatch(t *testing.T) {
+	data := []float64{3, 1, 4, 1, 5, 9, 2, 6}
+	rs := &RunningStats{}
+	for _, v := range data {
+		rs.Add(v)
+	}
+	batch := NewDataProcessor(data).ComputeStatistics()
+	if rs.Count() != len(data) {
+		t.Fatalf("Count = %d", rs.Count())
+	}
+	if !almostEqual(rs.Mean(), batch.Mean) || !almostEqual(rs.Std(), batch.Std) {
+		t.Fatalf("running mean/std = %v/%v, batch = %v/%v", rs.Mean(), rs.Std(), batch.Mean, batch.Std)
+	}
+	if rs.Min() != 1 || rs.Max() != 9 {
+		t.Fatalf("min/max = %v/%v", rs.Min(), rs.Max())
+	}
+}
+
+func TestCorrelation(t *testing.T) {
+	r, err := Correlation([]float64{1, 2, 3}, []float64{2, 4, 6})
+	if err != nil || !almostEqual(r, 1) {
+		t.Fatalf("perfect positive correlation = %v, %v", r, err)
+	}
+	r, _ = Correlation([]float64{1, 2, 3}, []float64{3, 2, 1})
+	if !almostEqual(r, -1) {
+		t.Fatalf("perfect negative correlation = %v", r)
+	}
+	if _, err := Correlation([]float64{1}, []float64{1, 2}); err == nil {
+		t.Fatal("length mismatch should error")
+	}
+	if _, err := Correlation([]float64{1}, []float64{2}); err == nil {
+		t.Fatal("too-short series should error")
+	}
+	if _, err := Correlation([]float64{1, 1}, []float64{2, 3}); err == nil {
+		t.Fatal("zero variance should error")
+	}
+}
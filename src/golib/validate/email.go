@@ -0,0 +1,40 @@
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// emailPattern is a pragmatic RFC 5322 subset: dot-atom local part
+// (with plus-tags) and a dotted domain of letter/digit/hyphen labels.
+var emailPattern = regexp.MustCompile(`^[a-zA-Z0-9!#$%&'*+/=?^_` + "`" + `{|}~-]+(?:\.[a-zA-Z0-9!#$%&'*+/=?^_` + "`" + `{|}~-]+)*@[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?(?:\.[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?)+$`)
+
+// ValidateEmailStrict validates an email address against a reasonable
+// RFC 5322 subset plus the standard length limits (64 characters for
+// the local part, 254 overall), returning a descriptive error.
+func ValidateEmailStrict(email string) error {
+	if email == "" {
+		return errors.New("email is empty")
+	}
+	if len(email) > 254 {
+		return errors.New("email exceeds 254 characters")
+	}
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return errors.New("email is missing @")
+	}
+	if at > 64 {
+		return errors.New("local part exceeds 64 characters")
+	}
+	if !emailPattern.MatchString(email) {
+		return fmt.Errorf("email %q is not a valid address", email)
+	}
+	return nil
+}
+
+// ValidateEmail reports whether the address passes ValidateEmailStrict.
+func ValidateEmail(email string) bool {
+	return ValidateEmailStrict(email) == nil
+}
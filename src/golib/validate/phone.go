@@ -0,0 +1,52 @@
+package validate
+
+import "strings"
+
+// phoneRules maps a region code to its expected national number length
+// and dialing prefix.
+var phoneRules = map[string]struct {
+	digits int
+	prefix string
+}{
+	"US": {digits: 10, prefix: "+1"},
+	"CA": {digits: 10, prefix: "+1"},
+	"DE": {digits: 10, prefix: "+49"},
+}
+
+// ValidatePhone strips formatting from a raw phone number, validates
+// it against simple per-region length rules, and returns an E.164-ish
+// normalized form (e.g. "+15551234567" for US). Unknown regions and
+// wrong-length numbers return ok=false.
+func ValidatePhone(raw string, region string) (normalized string, ok bool) {
+	rules, known := phoneRules[region]
+	if !known {
+		return "", false
+	}
+
+	var digits strings.Builder
+	for _, r := range raw {
+		if r >= '0' && r <= '9' {
+			digits.WriteRune(r)
+		}
+	}
+	national := digits.String()
+
+	// Only strip the country code when the caller actually dialed it:
+	// an explicit "+" or "00" international prefix, or more digits
+	// than a national number holds. A bare national number whose
+	// leading digits happen to match the country code (e.g. a German
+	// number in the 0491 area) must be left intact.
+	countryCode := strings.TrimPrefix(rules.prefix, "+")
+	trimmed := strings.TrimSpace(raw)
+	if strings.HasPrefix(trimmed, "+") || strings.HasPrefix(trimmed, "00") {
+		national = strings.TrimPrefix(national, "00")
+		national = strings.TrimPrefix(national, countryCode)
+	} else if len(national) > rules.digits {
+		national = strings.TrimPrefix(national, countryCode)
+	}
+
+	if len(national) != rules.digits {
+		return "", false
+	}
+	return rules.prefix + national, true
+}
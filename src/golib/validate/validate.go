@@ -0,0 +1,182 @@
+// Package validate provides input, email, phone, and credit-card
+// validation helpers.
+package validate
+
+import (
+	"fmt"
+	"strings"
+)
+
+// InputData represents input data for validation.
+type InputData struct {
+	Name     string
+	Email    string
+	Password string
+	Age      int
+}
+
+// ValidationRules configures the thresholds applied by ValidateInput.
+type ValidationRules struct {
+	MinPasswordLen int
+	MinAge         int
+	RequireEmail   bool
+}
+
+// DefaultValidationRules returns the rules historically hardcoded in
+// ValidateUserInput and ValidateAdminInput.
+func DefaultValidationRules() ValidationRules {
+	return ValidationRules{MinPasswordLen: 8, MinAge: 18, RequireEmail: true}
+}
+
+// ValidateInput validates input data against the given rules and
+// returns a message per violated rule.
+func ValidateInput(data InputData, rules ValidationRules) []string {
+	var errors []string
+	if data.Name == "" {
+		errors = append(errors, "Name is required")
+	}
+	if rules.RequireEmail {
+		if data.Email == "" {
+			errors = append(errors, "Email is required")
+		}
+		if !strings.Contains(data.Email, "@") {
+			errors = append(errors, "Invalid email format")
+		}
+	} else if data.Email != "" && !strings.Contains(data.Email, "@") {
+		errors = append(errors, "Invalid email format")
+	}
+	if data.Password == "" {
+		errors = append(errors, "Password is required")
+	}
+	if len(data.Password) < rules.MinPasswordLen {
+		errors = append(errors, fmt.Sprintf("Password must be at least %d characters", rules.MinPasswordLen))
+	}
+	if data.Age == 0 {
+		errors = append(errors, "Age is required")
+	}
+	if data.Age < rules.MinAge {
+		errors = append(errors, fmt.Sprintf("Must be at least %d years old", rules.MinAge))
+	}
+	return errors
+}
+
+// FieldError is a machine-readable validation error tied to the form
+// field that caused it.
+type FieldError struct {
+	Field   string
+	Code    string
+	Message string
+}
+
+// ValidateInputDetailed validates input data against the default rules
+// and returns structured errors so clients can map them to form
+// fields. The string-returning validators remain for compatibility.
+func ValidateInputDetailed(data InputData) []FieldError {
+	rules := DefaultValidationRules()
+	var errors []FieldError
+	if data.Name == "" {
+		errors = append(errors, FieldError{Field: "name", Code: "required", Message: "Name is required"})
+	}
+	if data.Email == "" {
+		errors = append(errors, FieldError{Field: "email", Code: "required", Message: "Email is required"})
+	}
+	if !strings.Contains(data.Email, "@") {
+		errors = append(errors, FieldError{Field: "email", Code: "format", Message: "Invalid email format"})
+	}
+	if data.Password == "" {
+		errors = append(errors, FieldError{Field: "password", Code: "required", Message: "Password is required"})
+	}
+	if len(data.Password) < rules.MinPasswordLen {
+		errors = append(errors, FieldError{
+			Field:   "password",
+			Code:    "min_length",
+			Message: fmt.Sprintf("Password must be at least %d characters", rules.MinPasswordLen),
+		})
+	}
+	if data.Age == 0 {
+		errors = append(errors, FieldError{Field: "age", Code: "required", Message: "Age is required"})
+	}
+	if data.Age < rules.MinAge {
+		errors = append(errors, FieldError{
+			Field:   "age",
+			Code:    "min_value",
+			Message: fmt.Sprintf("Must be at least %d years old", rules.MinAge),
+		})
+	}
+	return errors
+}
+
+// ValidateRegistration runs the default input checks plus the
+// cross-field password confirmation check. The mismatch error is
+// suppressed when the password itself is empty, since "Password is
+// required" already covers that case.
+func ValidateRegistration(data InputData, confirmPassword string) []string {
+	errors := ValidateInput(data, DefaultValidationRules())
+	if data.Password != "" && data.Password != confirmPassword {
+		errors = append(errors, "Passwords do not match")
+	}
+	return errors
+}
+
+// ValidateCreditCard reports whether a card number passes the Luhn
+// checksum and which brand its prefix and length indicate (Visa,
+// Mastercard, Amex, or unknown). Spaces and dashes are stripped before
+// validation.
+func ValidateCreditCard(number string) (bool, string) {
+	digits := strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' {
+			return -1
+		}
+		return r
+	}, number)
+
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return false, "unknown"
+		}
+	}
+	brand := detectCardBrand(digits)
+	if len(digits) < 12 || len(digits) > 19 {
+		return false, brand
+	}
+
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0, brand
+}
+
+// detectCardBrand classifies a digit string by issuer prefix and
+// length.
+func detectCardBrand(digits string) string {
+	switch {
+	case len(digits) == 15 && (strings.HasPrefix(digits, "34") || strings.HasPrefix(digits, "37")):
+		return "Amex"
+	case len(digits) == 16 && digits[:2] >= "51" && digits[:2] <= "55":
+		return "Mastercard"
+	case (len(digits) == 13 || len(digits) == 16 || len(digits) == 19) && strings.HasPrefix(digits, "4"):
+		return "Visa"
+	default:
+		return "unknown"
+	}
+}
+
+// ValidateUserInput validates user input data.
+func ValidateUserInput(data InputData) []string {
+	return ValidateInput(data, DefaultValidationRules())
+}
+
+// ValidateAdminInput validates admin input data.
+func ValidateAdminInput(data InputData) []string {
+	return ValidateInput(data, DefaultValidationRules())
+}
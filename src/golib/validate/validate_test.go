@@ -0,0 +1,150 @@
+package validate
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidateInputDefaults(t *testing.T) {
+	valid := InputData{Name: "Ada", Email: "ada@x.com", Password: "longenough", Age: 30}
+	if errs := ValidateUserInput(valid); len(errs) != 0 {
+		t.Fatalf("valid input got errors: %v", errs)
+	}
+	// User and admin wrappers share the default rules.
+	bad := InputData{}
+	if !reflect.DeepEqual(ValidateUserInput(bad), ValidateAdminInput(bad)) {
+		t.Fatal("user and admin validation should agree")
+	}
+	errs := ValidateUserInput(bad)
+	want := []string{
+		"Name is required",
+		"Email is required",
+		"Invalid email format",
+		"Password is required",
+		"Password must be at least 8 characters",
+		"Age is required",
+		"Must be at least 18 years old",
+	}
+	if !reflect.DeepEqual(errs, want) {
+		t.Fatalf("errors = %v, want %v", errs, want)
+	}
+}
+
+func TestValidateInputCustomRules(t *testing.T) {
+	rules := ValidationRules{MinPasswordLen: 4, MinAge: 21, RequireEmail: false}
+	data := InputData{Name: "x", Password: "abcd", Age: 18}
+	errs := ValidateInput(data, rules)
+	if !reflect.DeepEqual(errs, []string{"Must be at least 21 years old"}) {
+		t.Fatalf("errors = %v", errs)
+	}
+	// Optional email is still format-checked when present.
+	data.Email = "not-an-email"
+	errs = ValidateInput(data, rules)
+	if len(errs) != 2 || errs[0] != "Invalid email format" {
+		t.Fatalf("errors = %v", errs)
+	}
+}
+
+func TestValidateInputDetailed(t *testing.T) {
+	errs := ValidateInputDetailed(InputData{Name: "x", Email: "a@b.c", Password: "short", Age: 12})
+	want := []FieldError{
+		{Field: "password", Code: "min_length", Message: "Password must be at least 8 characters"},
+		{Field: "age", Code: "min_value", Message: "Must be at least 18 years old"},
+	}
+	if !reflect.DeepEqual(errs, want) {
+		t.Fatalf("detailed errors = %v", errs)
+	}
+}
+
+func TestValidateRegistration(t *testing.T) {
+	data := InputData{Name: "Ada", Email: "ada@x.com", Password: "longenough", Age: 30}
+	if errs := ValidateRegistration(data, "longenough"); len(errs) != 0 {
+		t.Fatalf("matching confirmation got errors: %v", errs)
+	}
+	errs := ValidateRegistration(data, "different")
+	if !reflect.DeepEqual(errs, []string{"Passwords do not match"}) {
+		t.Fatalf("errors = %v", errs)
+	}
+	// Empty password reports only the missing-password errors, not a
+	// redundant mismatch.
+	data.Password = ""
+	for _, e := range ValidateRegistration(data, "whatever") {
+		if e == "Passwords do not match" {
+			t.Fatal("mismatch reported for empty password")
+		}
+	}
+}
+
+func TestValidateCreditCard(t *testing.T) {
+	cases := []struct {
+		number string
+		ok     bool
+		brand  string
+	}{
+		{"4532 0151 1283 0366", true, "Visa"},
+		{"5500-0055-5555-5559", true, "Mastercard"},
+		{"340000000000009", true, "Amex"},
+		{"4532015112830367", false, "Visa"}, // bad checksum
+		{"1234", false, "unknown"},          // too short
+		{"4532a15112830366", false, "unknown"},
+	}
+	for _, c := range cases {
+		ok, brand := ValidateCreditCard(c.number)
+		if ok != c.ok || brand != c.brand {
+			t.Errorf("ValidateCreditCard(%q) = %v, %q; want %v, %q", c.number, ok, brand, c.ok, c.brand)
+		}
+	}
+}
+
+func TestValidateEmailStrict(t *testing.T) {
+	for _, good := range []string{"user@example.com", "first.last+tag@sub.domain.org"} {
+		if err := ValidateEmailStrict(good); err != nil {
+			t.Errorf("%q should validate: %v", good, err)
+		}
+	}
+	for _, bad := range []string{"", "no-at-sign", "user@", "@domain.com", "user@domain", "user@@x.com"} {
+		if err := ValidateEmailStrict(bad); err == nil {
+			t.Errorf("%q should not validate", bad)
+		}
+	}
+	if !ValidateEmail("user@example.com") || ValidateEmail("nope") {
+		t.Fatal("ValidateEmail disagrees with ValidateEmailStrict")
+	}
+}
+
+func TestValidatePhone(t *testing.T) {
+	got, ok := ValidatePhone("(555) 123-4567", "US")
+	if !ok || got != "+15551234567" {
+		t.Fatalf("US phone = %q, %v", got, ok)
+	}
+	if got, ok := ValidatePhone("+1 555 123 4567", "US"); !ok || got != "+15551234567" {
+		t.Fatalf("prefixed US phone = %q, %v", got, ok)
+	}
+	if _, ok := ValidatePhone("12345", "US"); ok {
+		t.Fatal("short number should fail")
+	}
+	if _, ok := ValidatePhone("5551234567", "FR"); ok {
+		t.Fatal("unknown region should fail")
+	}
+}
+
+func TestValidatePhoneCountryCodePrefixCollision(t *testing.T) {
+	// A bare German national number from the 0491 (Leer) area starts
+	// with the same digits as the country code and must not have them
+	// stripped.
+	if got, ok := ValidatePhone("4915123456", "DE"); !ok || got != "+494915123456" {
+		t.Fatalf("collision number = %q, %v; want +494915123456", got, ok)
+	}
+	// Explicit international prefixes are still stripped.
+	if got, ok := ValidatePhone("+49 491 5123456", "DE"); !ok || got != "+494915123456" {
+		t.Fatalf("+49 form = %q, %v", got, ok)
+	}
+	if got, ok := ValidatePhone("0049 491 5123456", "DE"); !ok || got != "+494915123456" {
+		t.Fatalf("0049 form = %q, %v", got, ok)
+	}
+	// A US number dialed with the country code but no "+" still
+	// normalizes by digit count.
+	if got, ok := ValidatePhone("1 555 123 4567", "US"); !ok || got != "+15551234567" {
+		t.Fatalf("11-digit US form = %q, %v", got, ok)
+	}
+}